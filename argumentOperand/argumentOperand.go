@@ -7,6 +7,10 @@ package argumentOperand
 import (
 	"errors"
 	"fmt"
+	"regexp"
+
+	"github.com/mozzzzy/arguments/v2/completion"
+	"github.com/mozzzzy/arguments/v2/source"
 )
 
 /*
@@ -14,13 +18,24 @@ import (
  */
 
 type Operand struct {
-	Key            string
-	Description    string
-	ValueType      string
-	DefaultValue   interface{}
-	Value          interface{}
-	Required       bool
-	Set            bool
+	Key         string
+	Description string
+	// Hidden operand is parsed but not shown in usage message.
+	Hidden bool
+	// Deprecated operand is parsed with a warning naming ReplacedBy.
+	Deprecated bool
+	ReplacedBy string
+	ValueType  string
+	// Kind of the value completed by completion scripts, "file" or "dir".
+	ValueHint string
+	// Complete returns the candidates of the value for completion scripts, like branch names.
+	Complete     completion.Func
+	DefaultValue interface{}
+	Value        interface{}
+	Required     bool
+	Set          bool
+	// Where Value comes from. It is set with Set.
+	Source         source.Source
 	Validator      func(interface{}, interface{}) error
 	ValidatorParam interface{}
 }
@@ -29,6 +44,8 @@ type Operand struct {
  * Constants and Package Scope Variables
  */
 
+var keyPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
 /*
  * Package Private Functions
  */
@@ -37,6 +54,12 @@ func validateRule(ope Operand) error {
 	if ope.Key == "" {
 		return errors.New("Key is required.")
 	}
+	if !keyPattern.MatchString(ope.Key) {
+		return errors.New(
+			fmt.Sprintf(
+				"Invalid key \"%v\". "+
+					"Key must be letters, digits, \"-\" and \"_\" without leading \"-\".", ope.Key))
+	}
 	if ope.ValueType == "" {
 		return errors.New("Value type is required.")
 	}
@@ -46,6 +69,32 @@ func validateRule(ope Operand) error {
 				"Required operand %v can't be specified its default value.",
 				ope.Key))
 	}
+	if ope.ValueHint != "" && ope.ValueHint != "file" && ope.ValueHint != "dir" {
+		return errors.New(
+			fmt.Sprintf("Unknown ValueHint \"%v\" of operand %v.", ope.ValueHint, ope.Key))
+	}
+	if err := validateValueType(ope); err != nil {
+		return errors.New(
+			fmt.Sprintf("Invalid operand %v. %v", ope.Key, err.Error()))
+	}
+	return nil
+}
+
+func validateValueType(ope Operand) error {
+	switch ope.ValueType {
+	case "string":
+		if _, ok := ope.DefaultValue.(string); ope.DefaultValue != nil && !ok {
+			return errors.New(
+				fmt.Sprintf("The ValueType is string. But default value is %T.", ope.DefaultValue))
+		}
+	case "int":
+		if _, ok := ope.DefaultValue.(int); ope.DefaultValue != nil && !ok {
+			return errors.New(
+				fmt.Sprintf("The ValueType is int. But default value is %T.", ope.DefaultValue))
+		}
+	default:
+		return errors.New(fmt.Sprintf("Unknown ValueType \"%v\".", ope.ValueType))
+	}
 	return nil
 }
 
@@ -82,6 +131,17 @@ func (ope *Operand) GetValue() (interface{}, error) {
 	return ope.Value, nil
 }
 
+// This function returns where the value returned by GetValue comes from.
+func (ope Operand) GetSource() source.Source {
+	if ope.Set {
+		return ope.Source
+	}
+	if ope.DefaultValue != nil {
+		return source.Source{Kind: source.Default}
+	}
+	return source.Source{Kind: source.Unset}
+}
+
 func (ope *Operand) SetValue(value interface{}) error {
 	if value == nil {
 		return errors.New("nil is invalid for SetValue func's param.")
@@ -126,3 +186,47 @@ func (ope Operand) Validate() error {
 	}
 	return nil
 }
+
+// This function returns the form used in usage synopsis, "<key>" if required and "[key]" otherwise.
+func (ope Operand) Synopsis() string {
+	if ope.Required {
+		return "<" + ope.Key + ">"
+	}
+	return "[" + ope.Key + "]"
+}
+
+// This function returns annotations shown after the description in usage message,
+// like "(required)" and "(default: 10)".
+func (ope Operand) Annotations() []string {
+	annotations := []string{}
+	// deprecated
+	if ope.Deprecated && ope.ReplacedBy != "" {
+		annotations = append(annotations, "(deprecated, use "+ope.ReplacedBy+")")
+	} else if ope.Deprecated {
+		annotations = append(annotations, "(deprecated)")
+	}
+	// required
+	if ope.Required {
+		annotations = append(annotations, "(required)")
+	}
+	// default value
+	switch defaultValue := ope.DefaultValue.(type) {
+	case string:
+		annotations = append(annotations, fmt.Sprintf("(default: \"%v\")", defaultValue))
+	case int:
+		annotations = append(annotations, fmt.Sprintf("(default: %v)", defaultValue))
+	}
+	return annotations
+}
+
+// This function returns the warning for the deprecated operand, or an empty string if it is not deprecated.
+func (ope Operand) DeprecationWarning() string {
+	if !ope.Deprecated {
+		return ""
+	}
+	str := fmt.Sprintf("Operand %v is deprecated.", ope.Key)
+	if ope.ReplacedBy != "" {
+		str += fmt.Sprintf(" Use %v instead.", ope.ReplacedBy)
+	}
+	return str
+}
@@ -7,6 +7,11 @@ package argumentOption
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mozzzzy/arguments/v2/completion"
+	"github.com/mozzzzy/arguments/v2/source"
 )
 
 /*
@@ -14,14 +19,44 @@ import (
  */
 
 type Option struct {
-	LongKey        string
-	ShortKey       string
-	Description    string
-	ValueType      string
-	DefaultValue   interface{}
-	Value          interface{}
-	Required       bool
-	Set            bool
+	LongKey      string
+	ShortKey     string
+	LongAliases  []string
+	ShortAliases []string
+	Description  string
+	// Name of the section in which the option is shown in usage message
+	Group string
+	// Hidden option is parsed but not shown in usage message.
+	Hidden bool
+	// Deprecated option is parsed with a warning naming ReplacedBy, like "--output".
+	Deprecated bool
+	ReplacedBy string
+	ValueType  string
+	// Name of the value shown in usage message instead of ValueType, like "WHEN".
+	ValueName string
+	// Kind of the value completed by completion scripts, "file" or "dir".
+	ValueHint string
+	// Complete returns the candidates of the value for completion scripts, like branch names.
+	Complete completion.Func
+	// If ValueOptional is true, the value can be given only as --<long key>=<value>.
+	// ImplicitValue is used when the option is given without value.
+	ValueOptional bool
+	ImplicitValue interface{}
+	// Sensitive value like password or token is redacted in messages and masked when it is prompted.
+	// It is returned only by GetSecretOpt of Args. ValueType must be "string".
+	Sensitive bool
+	// FileKey registers another option which takes the path of a file containing the value,
+	// like "token-file". "-" reads the value from standard input.
+	FileKey string
+	// If ValueFromFile is true, the value "@path" is replaced with the contents of the file,
+	// and "-" with the standard input. It is for large values like JSON documents and certificates.
+	ValueFromFile bool
+	DefaultValue  interface{}
+	Value         interface{}
+	Required      bool
+	Set           bool
+	// Where Value comes from. It is set with Set.
+	Source         source.Source
 	Validator      func(interface{}, interface{}) error
 	ValidatorParam interface{}
 }
@@ -30,6 +65,12 @@ type Option struct {
  * Constants and Package Scope Variables
  */
 
+// Redacted is shown instead of the value of a sensitive option.
+const Redacted = "[redacted]"
+
+var longKeyPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]+$`)
+var shortKeyPattern = regexp.MustCompile(`^[a-zA-Z0-9]$`)
+
 /*
  * Package Private Functions
  */
@@ -38,12 +79,131 @@ func validateRule(opt Option) error {
 	if opt.LongKey == "" && opt.ShortKey == "" {
 		return errors.New("Long key or short key is required.")
 	}
+	if opt.LongKey == "" && len(opt.LongAliases) != 0 {
+		return errors.New(
+			fmt.Sprintf("Long aliases %v require a long key.", opt.LongAliases))
+	}
+	if opt.ShortKey == "" && len(opt.ShortAliases) != 0 {
+		return errors.New(
+			fmt.Sprintf("Short aliases %v require a short key.", opt.ShortAliases))
+	}
+	for _, longKey := range opt.GetLongKeys() {
+		if !longKeyPattern.MatchString(longKey) {
+			return errors.New(
+				fmt.Sprintf(
+					"Invalid long key \"%v\". "+
+						"Long key must be two or more letters, digits, \"-\" and \"_\" "+
+						"without leading \"-\".", longKey))
+		}
+	}
+	for _, shortKey := range opt.GetShortKeys() {
+		if !shortKeyPattern.MatchString(shortKey) {
+			return errors.New(
+				fmt.Sprintf(
+					"Invalid short key \"%v\". "+
+						"Short key must be one letter or digit without leading \"-\".", shortKey))
+		}
+	}
+	for _, keys := range [][]string{opt.GetLongKeys(), opt.GetShortKeys()} {
+		for index, key := range keys {
+			for _, otherKey := range keys[index+1:] {
+				if key == otherKey {
+					return errors.New(
+						fmt.Sprintf("Key \"%v\" is specified twice in option %v.", key, opt))
+				}
+			}
+		}
+	}
+	if opt.FileKey != "" && !longKeyPattern.MatchString(opt.FileKey) {
+		return errors.New(
+			fmt.Sprintf("Invalid FileKey \"%v\" of option %v.", opt.FileKey, opt))
+	}
 	if opt.Required && opt.DefaultValue != nil {
 		return errors.New(
 			fmt.Sprintf(
 				"Required option --%v -%v can't be specified its default value.",
 				opt.LongKey, opt.ShortKey))
 	}
+	if err := validateValueType(opt); err != nil {
+		return errors.New(
+			fmt.Sprintf("Invalid option --%v -%v. %v", opt.LongKey, opt.ShortKey, err.Error()))
+	}
+	return nil
+}
+
+func validateValueType(opt Option) error {
+	switch opt.ValueType {
+	case "":
+		if opt.DefaultValue != nil {
+			return errors.New("Option without ValueType can't be specified its default value.")
+		}
+		if opt.ValueOptional {
+			return errors.New("Option without ValueType can't be specified ValueOptional.")
+		}
+		if opt.ValueHint != "" {
+			return errors.New("Option without ValueType can't be specified ValueHint.")
+		}
+		if opt.Complete != nil {
+			return errors.New("Option without ValueType can't be specified Complete.")
+		}
+		if opt.Sensitive {
+			return errors.New("Option without ValueType can't be Sensitive.")
+		}
+		if opt.FileKey != "" {
+			return errors.New("Option without ValueType can't be specified FileKey.")
+		}
+		if opt.ValueFromFile {
+			return errors.New("Option without ValueType can't be specified ValueFromFile.")
+		}
+		return nil
+	case "string", "int":
+	default:
+		return errors.New(fmt.Sprintf("Unknown ValueType \"%v\".", opt.ValueType))
+	}
+
+	if opt.Sensitive && opt.ValueType != "string" {
+		return errors.New("Sensitive option requires ValueType string.")
+	}
+	if opt.Sensitive && opt.DefaultValue != nil {
+		return errors.New("Sensitive option can't be specified its default value.")
+	}
+	if opt.ValueHint != "" && opt.ValueHint != "file" && opt.ValueHint != "dir" {
+		return errors.New(fmt.Sprintf("Unknown ValueHint \"%v\".", opt.ValueHint))
+	}
+	if err := validateValue(opt.ValueType, opt.DefaultValue); err != nil {
+		return errors.New(fmt.Sprintf("Invalid default value. %v", err.Error()))
+	}
+	if !opt.ValueOptional {
+		return nil
+	}
+	if opt.LongKey == "" {
+		return errors.New("Option whose value is optional requires a long key.")
+	}
+	if opt.ImplicitValue == nil {
+		return errors.New("Option whose value is optional requires ImplicitValue.")
+	}
+	if err := validateValue(opt.ValueType, opt.ImplicitValue); err != nil {
+		return errors.New(fmt.Sprintf("Invalid implicit value. %v", err.Error()))
+	}
+	return nil
+}
+
+func validateValue(valueType string, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch valueType {
+	case "string":
+		if _, ok := value.(string); !ok {
+			return errors.New(
+				fmt.Sprintf("The ValueType is string. But the value is %T.", value))
+		}
+	case "int":
+		if _, ok := value.(int); !ok {
+			return errors.New(
+				fmt.Sprintf("The ValueType is int. But the value is %T.", value))
+		}
+	}
 	return nil
 }
 
@@ -67,6 +227,70 @@ func New(opt Option) (*Option, error) {
  * Public Methods
  */
 
+// This function returns the long key followed by its aliases.
+func (opt Option) GetLongKeys() []string {
+	keys := []string{}
+	if opt.LongKey != "" {
+		keys = append(keys, opt.LongKey)
+	}
+	return append(keys, opt.LongAliases...)
+}
+
+// This function returns the short key followed by its aliases.
+func (opt Option) GetShortKeys() []string {
+	keys := []string{}
+	if opt.ShortKey != "" {
+		keys = append(keys, opt.ShortKey)
+	}
+	return append(keys, opt.ShortAliases...)
+}
+
+// This function returns ValueName, or ValueType if ValueName is not specified.
+func (opt Option) GetValueName() string {
+	if opt.ValueName != "" {
+		return opt.ValueName
+	}
+	return opt.ValueType
+}
+
+// This function returns the warning for the deprecated option, or an empty string if it is not deprecated.
+func (opt Option) DeprecationWarning() string {
+	if !opt.Deprecated {
+		return ""
+	}
+	str := fmt.Sprintf("Option %v is deprecated.", opt.Name())
+	if opt.ReplacedBy != "" {
+		str += fmt.Sprintf(" Use %v instead.", opt.ReplacedBy)
+	}
+	return str
+}
+
+// This function returns the option registered for FileKey, which takes the path of the file.
+func (opt Option) FileOption() Option {
+	return Option{
+		LongKey: opt.FileKey,
+		Description: fmt.Sprintf(
+			"read the value of %v from FILE. \"-\" reads standard input.", strings.Fields(opt.Name())[0]),
+		Group:     opt.Group,
+		Hidden:    opt.Hidden,
+		ValueType: "string",
+		ValueName: "FILE",
+		ValueHint: "file",
+	}
+}
+
+// This function returns the name of the option used in messages, like "--long-key -s".
+func (opt Option) Name() string {
+	var keys []string
+	if opt.LongKey != "" {
+		keys = append(keys, "--"+opt.LongKey)
+	}
+	if opt.ShortKey != "" {
+		keys = append(keys, "-"+opt.ShortKey)
+	}
+	return strings.Join(keys, " ")
+}
+
 func (opt *Option) GetValue() (interface{}, error) {
 	if !opt.Set && opt.DefaultValue == nil {
 		return nil, errors.New(
@@ -80,6 +304,17 @@ func (opt *Option) GetValue() (interface{}, error) {
 	return opt.Value, nil
 }
 
+// This function returns where the value returned by GetValue comes from.
+func (opt Option) GetSource() source.Source {
+	if opt.Set {
+		return opt.Source
+	}
+	if opt.DefaultValue != nil {
+		return source.Source{Kind: source.Default}
+	}
+	return source.Source{Kind: source.Unset}
+}
+
 func (opt *Option) SetValue(value interface{}) error {
 	if value == nil {
 		return errors.New("nil is invalid for SetValue func's param.")
@@ -112,10 +347,15 @@ func (opt *Option) SetValue(value interface{}) error {
 	return nil
 }
 
-func (opt Option) ValueRequired() bool {
+// This function returns true if the option takes a value, whether it is optional or not.
+func (opt Option) TakesValue() bool {
 	return opt.ValueType == "string" || opt.ValueType == "int"
 }
 
+func (opt Option) ValueRequired() bool {
+	return opt.TakesValue() && !opt.ValueOptional
+}
+
 func (opt Option) Validate() error {
 	// Required but not set
 	if opt.Required && opt.Value == nil {
@@ -130,23 +370,77 @@ func (opt Option) Validate() error {
 	return nil
 }
 
+// This function returns the form used in usage synopsis, like "-n string" or "--color[=WHEN]".
+// The short key is preferred unless the value is optional.
+func (opt Option) Synopsis() string {
+	if opt.ValueOptional {
+		return "--" + opt.LongKey + "[=" + opt.GetValueName() + "]"
+	}
+	str := "--" + opt.LongKey
+	if opt.ShortKey != "" {
+		str = "-" + opt.ShortKey
+	}
+	if opt.ValueRequired() {
+		str += " " + opt.GetValueName()
+	}
+	return str
+}
+
+// This function returns annotations shown after the description in usage message,
+// like "(required)" and "(default: 10)".
+func (opt Option) Annotations() []string {
+	annotations := []string{}
+	// deprecated
+	if opt.Deprecated && opt.ReplacedBy != "" {
+		annotations = append(annotations, "(deprecated, use "+opt.ReplacedBy+")")
+	} else if opt.Deprecated {
+		annotations = append(annotations, "(deprecated)")
+	}
+	// required
+	if opt.Required {
+		annotations = append(annotations, "(required)")
+	}
+	// value from file
+	if opt.ValueFromFile {
+		annotations = append(annotations, "(@file or - for stdin)")
+	}
+	// default value
+	switch defaultValue := opt.DefaultValue.(type) {
+	case string:
+		annotations = append(annotations, fmt.Sprintf("(default: \"%v\")", defaultValue))
+	case int:
+		annotations = append(annotations, fmt.Sprintf("(default: %v)", defaultValue))
+	}
+	return annotations
+}
+
 func (opt Option) String() string {
 	str := ""
-	// long key
-	if opt.LongKey != "" {
-		str += "--" + opt.LongKey
+	// long key and aliases
+	for index, longKey := range opt.GetLongKeys() {
+		if index != 0 {
+			str += "|"
+		}
+		str += "--" + longKey
 	}
-	// short key
-	if opt.ShortKey != "" {
-		if len(str) != 0 {
+	// optional value is attached to the long keys
+	if opt.TakesValue() && opt.ValueOptional {
+		str += "[=" + opt.GetValueName() + "]"
+	}
+	// short key and aliases
+	for index, shortKey := range opt.GetShortKeys() {
+		if index == 0 && len(str) != 0 {
 			str += " "
 		}
-		str += "-" + opt.ShortKey
+		if index != 0 {
+			str += "|"
+		}
+		str += "-" + shortKey
 	}
 	// value type
-	if opt.ValueType != "" {
+	if opt.ValueRequired() {
 		str += " "
-		str += opt.ValueType
+		str += opt.GetValueName()
 	}
 	return str
 }
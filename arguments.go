@@ -7,13 +7,21 @@ package arguments
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/mozzzzy/arguments/v2/argumentOption"
 	"github.com/mozzzzy/arguments/v2/argumentOperand"
+	"github.com/mozzzzy/arguments/v2/helpFormatter"
 	"github.com/mozzzzy/arguments/v2/operandList"
 	"github.com/mozzzzy/arguments/v2/optionList"
+	"github.com/mozzzzy/arguments/v2/source"
+	"github.com/mozzzzy/arguments/v2/suggestion"
+	"github.com/mozzzzy/arguments/v2/tokenizer"
+	"github.com/mozzzzy/arguments/v2/validator"
 )
 
 /*
@@ -22,22 +30,261 @@ import (
 
 type Args struct {
 	Executed   string
+	// Max edit distance of "did you mean" suggestions.
+	// 0 uses suggestion.DefaultMaxDistance and a negative value disables suggestions.
+	SuggestDistance int
+	// Accept unique prefixes of long keys, like --verb for --verbose.
+	AllowAbbrev bool
+	// Register --help -h automatically. If it is specified, Parse writes usage to Output and returns ErrHelp.
+	AutoHelp bool
+	// Register --version -V automatically. If it is specified, Parse writes Version
+	// (or the build information if Version is empty) to Output and returns ErrVersion.
+	// --version=json writes it as JSON.
+	AutoVersion bool
+	Version     string
+	// Optional options are collapsed into "[options]" in usage synopsis if there are more than this.
+	// 0 uses DefaultSynopsisMaxOptions.
+	SynopsisMaxOptions int
+	// Description, examples and epilog of the program shown in usage
+	Description string
+	Examples    []Example
+	Epilog      string
+	// Environment variables which the program reads, shown in usage and man page
+	Environment []EnvironmentVariable
+	// text/template used for usage. DefaultHelpTemplate is used if empty.
+	HelpTemplate string
+	// Width of usage. Descriptions are wrapped to fit in it.
+	// 0 detects the width of the terminal and a negative value disables wrapping.
+	HelpWidth int
+	// Receives warnings like deprecation of options. Warnings are written to os.Stderr if nil.
+	WarningHandler func(warning string)
+	// Expand "@file" arguments into the arguments written in the file.
+	ResponseFiles bool
+	// Expand $NAME and ${NAME} in the command line given to ParseString.
+	ExpandEnv bool
+	// Prompt for the values of required options and operands which are not given.
+	// Prompts are shown only if PromptInput is a terminal, unless PromptAlways is true.
+	PromptMissing bool
+	PromptAlways  bool
+	// Input and output of prompts. os.Stdin and os.Stderr are used if nil.
+	PromptInput  io.Reader
+	PromptOutput io.Writer
+	// Standard input read by "-" given to the options of FileKey and ValueFromFile.
+	// os.Stdin is used if nil. It is read at most once.
+	Stdin io.Reader
+	// Max size in bytes of the values read from files and Stdin.
+	// 0 uses DefaultMaxValueSize and a negative value disables the limit.
+	MaxValueSize int64
+	// Output of usage and version written by AutoHelp and AutoVersion,
+	// and of completion candidates written for CompleteCommand. os.Stdout is used if nil.
+	Output io.Writer
 	optionList optionList.OptionList
 	operandList operandList.OperandList
+	groups     []OptionGroup
+	// Name of the option which has read Stdin
+	stdinReadBy string
+}
+
+// OptionGroup is a section of options in usage message.
+// Options are grouped by their Group field.
+type OptionGroup struct {
+	Name        string
+	Description string
+}
+
+// UnknownArgumentError is returned when an unknown option is specified,
+// or when the value of an argument like the shell of WriteCompletion is unknown.
+// Suggestions holds the close candidates so that front-ends can present them by themselves.
+type UnknownArgumentError struct {
+	Arg         string
+	Name        string
+	Suggestions []string
+}
+
+// AmbiguousOptionError is returned when an abbreviated long key matches several options.
+type AmbiguousOptionError struct {
+	Arg        string
+	Candidates []string
 }
 
 /*
  * Constants and Package Scope Variables
  */
 
+// ErrHelp is returned by Parse when --help is specified and AutoHelp is true.
+// The usage has already been written, so the program should exit with status 0.
+var ErrHelp = errors.New("Help is requested.")
+
+const DefaultSynopsisMaxOptions = 5
+
+const DefaultMaxValueSize = 1 << 20
+
 /*
  * Private Methods
  */
 
+// This function returns the base name of the executed file.
+// Before Parse is called, os.Args[0] is used instead of Executed.
+func (args Args) programName() string {
+	executed := args.Executed
+	if executed == "" && len(os.Args) > 0 {
+		executed = os.Args[0]
+	}
+	return filepath.Base(executed)
+}
+
+func (args Args) helpWidth() int {
+	if args.HelpWidth == 0 {
+		return helpFormatter.DetectWidth()
+	}
+	return args.HelpWidth
+}
+
+func (args Args) warn(warning string) {
+	if warning == "" {
+		return
+	}
+	if args.WarningHandler != nil {
+		args.WarningHandler(warning)
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Warning: "+warning)
+}
+
+func (args Args) warnDeprecatedValue(name string, validatorParam interface{}, value interface{}) {
+	replacement, ok := validator.DeprecatedValue(validatorParam, value)
+	if !ok {
+		return
+	}
+	warning := fmt.Sprintf("Value \"%v\" of %v is deprecated.", value, name)
+	if replacement != "" {
+		warning += fmt.Sprintf(" Use \"%v\" instead.", replacement)
+	}
+	args.warn(warning)
+}
+
+func (args Args) output() io.Writer {
+	if args.Output == nil {
+		return os.Stdout
+	}
+	return args.Output
+}
+
+// This function registers the options of AutoHelp and AutoVersion.
+func (args *Args) addAutoOpts() error {
+	if args.AutoHelp {
+		if err := args.addHelpOpt(); err != nil {
+			return err
+		}
+	}
+	if args.AutoVersion {
+		if err := args.addVersionOpt(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// This function registers --help and also -h unless -h is already used.
+func (args *Args) addHelpOpt() error {
+	if _, err := args.optionList.GetOpt("--help"); err == nil {
+		return nil
+	}
+	opt := argumentOption.Option{
+		LongKey:     "help",
+		ShortKey:    "h",
+		Description: "show help message and exit.",
+	}
+	if _, err := args.optionList.GetOpt("-h"); err == nil {
+		opt.ShortKey = ""
+	}
+	return args.optionList.AddOption(opt)
+}
+
+// This function returns true and the attached value if the option of longKey is specified in command line.
+// Only the keys are checked, so that other errors in command line don't hide the usage or the version.
+func (args Args) optRequested(argv []string, longKey string) (string, bool) {
+	for index, argStr := range argv {
+		if index == 0 || !optionList.IsOptKey(argStr) {
+			continue
+		}
+		value := ""
+		if separator := strings.Index(argStr, "="); strings.HasPrefix(argStr, "--") && separator >= 0 {
+			value = argStr[separator+1:]
+			argStr = argStr[:separator]
+		}
+		// "--=value" has no long key.
+		if argStr == "--" {
+			continue
+		}
+		key, err := args.resolveAbbrev(argStr)
+		if err != nil {
+			continue
+		}
+		if opt, err := args.optionList.GetOpt(key); err == nil && opt.LongKey == longKey {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// This function returns the long key which the abbreviated key stands for.
+// If the key matches an option exactly or abbreviation is not allowed, the key is returned as it is.
+func (args Args) resolveAbbrev(key string) (string, error) {
+	// The empty long key of "--=value" would be a prefix of every long key.
+	if key == "--" {
+		return key, errors.New("Long key is empty.")
+	}
+	if !args.AllowAbbrev || !strings.HasPrefix(key, "--") {
+		return key, nil
+	}
+	if _, err := args.optionList.GetOpt(key); err == nil {
+		return key, nil
+	}
+	candidates := args.optionList.GetLongKeysWithPrefix(key)
+	switch len(candidates) {
+	case 0:
+		return key, nil
+	case 1:
+		return candidates[0], nil
+	}
+	return key, &AmbiguousOptionError{Arg: key, Candidates: candidates}
+}
+
+func (args Args) suggestDistance() int {
+	if args.SuggestDistance == 0 {
+		return suggestion.DefaultMaxDistance
+	}
+	return args.SuggestDistance
+}
+
+func (args Args) suggest(input string, candidates []string) []string {
+	if args.suggestDistance() < 0 {
+		return []string{}
+	}
+	return suggestion.Suggest(input, candidates, args.suggestDistance())
+}
+
+func (args Args) suggestOpt(key string) []string {
+	if args.suggestDistance() < 0 {
+		return []string{}
+	}
+	return args.optionList.Suggest(key, args.suggestDistance())
+}
+
 /*
  * Public Methods
  */
 
+// Clone returns a copy of args. Parsing the copy doesn't modify the options and operands of args.
+func (args Args) Clone() Args {
+	clone := args
+	clone.optionList = args.optionList.Clone()
+	clone.operandList = args.operandList.Clone()
+	clone.groups = append([]OptionGroup{}, args.groups...)
+	return clone
+}
+
 func (args *Args) AddOption(opt argumentOption.Option) error {
 	return args.optionList.AddOption(opt)
 }
@@ -46,10 +293,42 @@ func (args *Args) AddOptions(opts []argumentOption.Option) error {
 	return args.optionList.AddOptions(opts)
 }
 
+// AddGroup adds a section of options.
+// Sections are shown in the order they are added, after the options without Group.
+func (args *Args) AddGroup(group OptionGroup) error {
+	if group.Name == "" {
+		return errors.New("Name of group is required.")
+	}
+	for _, addedGroup := range args.groups {
+		if addedGroup.Name == group.Name {
+			return errors.New(
+				fmt.Sprintf("Group \"%v\" is already added.", group.Name))
+		}
+	}
+	args.groups = append(args.groups, group)
+	return nil
+}
+
+func (args *Args) AddGroups(groups []OptionGroup) error {
+	for index := 0; index < len(groups); index++ {
+		if err := args.AddGroup(groups[index]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (args Args) GetOpt(key string) (interface{}, error) {
 	return args.optionList.Get(key)
 }
 
+// GetSecretOpt returns the value of a sensitive option.
+// GetOpt and GetStringOpt return an error for sensitive options, so that their values
+// are not exposed by accident.
+func (args Args) GetSecretOpt(key string) (string, error) {
+	return args.optionList.GetSecret(key)
+}
+
 func (args Args) GetIntOpt(key string) (int, error) {
 	return args.optionList.GetInt(key)
 }
@@ -62,6 +341,16 @@ func (args Args) OptIsSet(key string) bool {
 	return args.optionList.IsSet(key)
 }
 
+// GetOptSource returns where the value of the option comes from,
+// like the position in argv, the response file or the default value.
+func (args Args) GetOptSource(key string) (source.Source, error) {
+	opt, err := args.optionList.GetOpt(key)
+	if err != nil {
+		return source.Source{}, err
+	}
+	return opt.GetSource(), nil
+}
+
 func (args *Args) AddOperand(ope argumentOperand.Operand) error {
 	return args.operandList.AddOperand(ope)
 }
@@ -86,13 +375,76 @@ func (args Args) OperandIsSet(key string) bool {
 	return args.operandList.IsSet(key)
 }
 
+// GetOperandSource returns where the value of the operand comes from.
+func (args Args) GetOperandSource(key string) (source.Source, error) {
+	ope, err := args.operandList.GetOpe(key)
+	if err != nil {
+		return source.Source{}, err
+	}
+	return ope.GetSource(), nil
+}
+
 func (args *Args) Parse() error {
+	if len(os.Args) > 1 && os.Args[1] == CompleteCommand {
+		if err := args.addAutoOpts(); err != nil {
+			return err
+		}
+		args.Executed = os.Args[0]
+		args.writeCandidates(os.Args[2:])
+		return ErrComplete
+	}
+	return args.ParseArgs(os.Args)
+}
+
+// ParseArgs parses argv instead of os.Args. argv[0] is the executed file name.
+func (args *Args) ParseArgs(argv []string) error {
+	if len(argv) > 0 {
+		args.Executed = argv[0]
+	}
+	if err := args.addAutoOpts(); err != nil {
+		return err
+	}
+	sources := []source.Source{}
+	for index := range argv {
+		sources = append(sources, source.Source{Kind: source.Argv, Index: index})
+	}
+	if args.ResponseFiles {
+		var err error
+		if argv, sources, err = expandResponseFiles(argv, args.isFileValueKey); err != nil {
+			return err
+		}
+	}
+	return args.parse(argv, sources)
+}
+
+// ParseString splits the command line like shell and parses it, like "deploy --env \"prod eu\" -n 3".
+// The first word is the executed file name. If ExpandEnv is true, $NAME and ${NAME} are expanded.
+// *tokenizer.SyntaxError is returned with the position of an unterminated quote.
+func (args *Args) ParseString(commandLine string) error {
+	tok := tokenizer.Tokenizer{ExpandEnv: args.ExpandEnv}
+	tokens, err := tok.Tokenize(commandLine)
+	if err != nil {
+		return err
+	}
+	argv := []string{}
+	for _, token := range tokens {
+		argv = append(argv, token.Value)
+	}
+	if len(argv) == 0 {
+		return errors.New("Command line is empty.")
+	}
+	return args.ParseArgs(argv)
+}
+
+// This function parses the options and operands of argv.
+// If an argument is invalid, its index in argv is returned with the error.
+func (args *Args) parseArguments(argv []string, sources []source.Source) (int, error) {
 	operandCount := 0
 	operandKeys := args.operandList.GetOpeKeys()
 
 	// Parse arguments to executed file name, options and operands
-	for index := 0; index < len(os.Args); index++ {
-		argStr := os.Args[index]
+	for index := 0; index < len(argv); index++ {
+		argStr := argv[index]
 
 		// executed file name
 		if index == 0 {
@@ -102,49 +454,72 @@ func (args *Args) Parse() error {
 
 		// option
 		if optionList.IsOptKey(argStr) {
+			// The source of the value is the position of the key.
+			src := sources[index]
+			// --<long key>=<value>
+			attachedValue := ""
+			hasAttachedValue := false
+			if separator := strings.Index(argStr, "="); strings.HasPrefix(argStr, "--") && separator >= 0 {
+				attachedValue = argStr[separator+1:]
+				hasAttachedValue = true
+				argStr = argStr[:separator]
+			}
+			resolvedKey, err := args.resolveAbbrev(argStr)
+			if err != nil {
+				return index, err
+			}
+			argStr = resolvedKey
 			// This opt is not a pointer.
 			// So even if we modify this opt, the original opt in optionList is not modified.
 			opt, err := args.optionList.GetOpt(argStr)
 			if err != nil {
-				return errors.New(
-					fmt.Sprintf(
-						"Failed to get option setting of \"%v\" from option list. %v",
-						argStr,
-						err.Error()))
+				return index, &UnknownArgumentError{Arg: argStr, Suggestions: args.suggestOpt(argStr)}
 			}
-			valueStr := ""
-			if opt.ValueRequired() {
+			var value interface{}
+			switch {
+			case hasAttachedValue:
+				if !opt.TakesValue() {
+					return index, errors.New(
+						fmt.Sprintf("option %v doesn't take value but \"%v\" is specified.", argStr, attachedValue))
+				}
+				if value, err = args.optionValue(opt, attachedValue); err != nil {
+					return index, err
+				}
+				src = valueSource(opt, attachedValue, src)
+			case opt.ValueOptional:
+				// The value of the next argument is never used, because it may be an operand.
+				value = opt.ImplicitValue
+			case opt.ValueRequired():
 				index++
-				if index >= len(os.Args) || optionList.IsOptKey(os.Args[index]) {
-					return errors.New(
+				if index >= len(argv) || optionList.IsOptKey(argv[index]) {
+					// The error is located at the option key, because the value is missing.
+					return index - 1, errors.New(
 						fmt.Sprintf("option %v requires value but is not speficied.", argStr))
 				}
-				valueStr = os.Args[index]
-			}
-			var value interface{}
-			switch opt.ValueType {
-			case "":
-				value = nil
-			case "string":
-				value = valueStr
-			case "int":
-				valueInt, err := strconv.Atoi(valueStr)
-				if err != nil {
-					return err
+				if value, err = args.optionValue(opt, argv[index]); err != nil {
+					return index, err
 				}
-				value = valueInt
+				src = valueSource(opt, argv[index], src)
+			}
+			args.warn(opt.DeprecationWarning())
+			// Sensitive value is never written in warnings.
+			if !opt.Sensitive {
+				args.warnDeprecatedValue(opt.Name(), opt.ValidatorParam, value)
 			}
 			if err := args.optionList.Set(argStr, value); err != nil {
-				return errors.New(
+				return index, errors.New(
 					fmt.Sprintf("Failed to set option \"%v\". %v", argStr, err.Error()))
 			}
+			if err := args.optionList.SetSource(argStr, src); err != nil {
+				return index, err
+			}
 			continue
 		}
 
 		// If argStr does not have prefix "--" and "-",
 		// this argStr is operand.
 		if operandCount >= len(operandKeys) {
-			return errors.New(fmt.Sprintf("To many operands %v", argStr))
+			return index, errors.New(fmt.Sprintf("To many operands %v", argStr))
 		}
 
 		opeKey := operandKeys[operandCount]
@@ -154,60 +529,123 @@ func (args *Args) Parse() error {
 		// So even if we modify this operand, the original operand in operandList is not modified.
 		operand, err := args.operandList.GetOpe(opeKey)
 		if err != nil {
-			return err
+			return index, err
 		}
 
-		var value interface{}
-		switch operand.ValueType {
-		case "":
-			value = nil
-		case "string":
-			value = argStr
-		case "int":
-			valueInt, err := strconv.Atoi(argStr)
-			if err != nil {
-				return errors.New(fmt.Sprintf(
-					"Failed to parse operand %v \"%v\". %v",
-					opeKey,
-					argStr,
-					err.Error()))
-			}
-			value = valueInt
+		value, err := convertValue(operand.ValueType, argStr)
+		if err != nil {
+			return index, errors.New(fmt.Sprintf(
+				"Failed to parse operand %v \"%v\". %v",
+				opeKey,
+				argStr,
+				err.Error()))
 		}
+		args.warn(operand.DeprecationWarning())
+		args.warnDeprecatedValue(opeKey, operand.ValidatorParam, value)
 		if err := args.operandList.Set(opeKey, value); err != nil {
-			return errors.New(
+			return index, errors.New(
 				fmt.Sprintf("Failed to set operand \"%v\". %v", argStr, err.Error()))
 		}
+		if err := args.operandList.SetSource(opeKey, sources[index]); err != nil {
+			return index, err
+		}
 	}
-	return args.Validate()
+	return len(argv), nil
 }
 
-func (arg Args) String() string {
-	str := ""
-	str += "\nUsage: \n"
-	str += "  " + arg.Executed + " Options Operands"
-	str += "\n"
+// This function parses argv after the options of AutoHelp and AutoVersion are registered.
+// sources are where the arguments of argv come from.
+func (args *Args) parse(argv []string, sources []source.Source) error {
+	if args.AutoHelp {
+		if _, ok := args.optRequested(argv, "help"); ok {
+			fmt.Fprintln(args.output(), args)
+			return ErrHelp
+		}
+	}
+	if args.AutoVersion {
+		if format, ok := args.optRequested(argv, "version"); ok {
+			if err := args.printVersion(format); err != nil {
+				return err
+			}
+			return ErrVersion
+		}
+	}
 
-	str += "\n"
-	str += arg.optionList.String()
-	str += "\n"
-	str += arg.operandList.String()
+	if index, err := args.parseArguments(argv, sources); err != nil {
+		return locateError(err, sources[index])
+	}
+	if err := args.readValueFiles(); err != nil {
+		return err
+	}
+	if err := args.promptMissing(); err != nil {
+		return err
+	}
+	return args.Validate()
+}
 
-	return str
+// Synopsis returns the usage synopsis like "prog [-v] --name string <src> [dst]".
+func (arg Args) Synopsis() string {
+	maxOptional := arg.SynopsisMaxOptions
+	if maxOptional == 0 {
+		maxOptional = DefaultSynopsisMaxOptions
+	}
+	strs := []string{arg.programName()}
+	if opts := arg.optionList.Synopsis(maxOptional); opts != "" {
+		strs = append(strs, opts)
+	}
+	if opes := arg.operandList.Synopsis(); opes != "" {
+		strs = append(strs, opes)
+	}
+	return strings.Join(strs, " ")
 }
 
 func (arg Args) Validate() error {
-	if err := arg.optionList.Validate(); err != nil {
-		return err;
+	err := arg.optionList.Validate()
+	if err == nil {
+		err = arg.operandList.Validate()
+	}
+
+	// Suggest useable values which are close to the specified one.
+	var useableErr *validator.UseableError
+	if !errors.As(err, &useableErr) || useableErr.Value == argumentOption.Redacted {
+		return err
 	}
+	useableErr.Suggestions = arg.suggest(useableErr.Value, useableErr.Useable)
+	return err
+}
 
-	return arg.operandList.Validate()
+func (err *AmbiguousOptionError) Error() string {
+	return fmt.Sprintf(
+		"Option \"%v\" is ambiguous. It matches %v.",
+		err.Arg, strings.Join(err.Candidates, ", "))
+}
+
+func (err *UnknownArgumentError) Error() string {
+	msg := fmt.Sprintf("Unknown option \"%v\".", err.Arg)
+	if err.Name != "" {
+		msg = fmt.Sprintf("Invalid value of %v \"%v\".", err.Name, err.Arg)
+	}
+	if len(err.Suggestions) == 0 {
+		return msg
+	}
+	return msg + fmt.Sprintf(" Did you mean \"%v\"?", strings.Join(err.Suggestions, "\" or \""))
 }
 
 /*
  * Package Private Functions
  */
 
+// This function converts the string given in command line to the value of valueType.
+func convertValue(valueType string, valueStr string) (interface{}, error) {
+	switch valueType {
+	case "string":
+		return valueStr, nil
+	case "int":
+		return strconv.Atoi(valueStr)
+	}
+	return nil, nil
+}
+
 /*
  * Public Functions
  */
@@ -1,18 +1,33 @@
 package arguments_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/mozzzzy/arguments/v2"
 	"github.com/mozzzzy/arguments/v2/argumentOperand"
 	"github.com/mozzzzy/arguments/v2/argumentOption"
+	"github.com/mozzzzy/arguments/v2/completion"
+	"github.com/mozzzzy/arguments/v2/helpFormatter"
+	"github.com/mozzzzy/arguments/v2/source"
+	"github.com/mozzzzy/arguments/v2/validator"
 )
 
 /*
  * Variables
  */
 
+// Run "go test -update" to rewrite golden files in testdata.
+var update = flag.Bool("update", false, "update golden files")
+
 /*
  * Functions
  */
@@ -35,6 +50,68 @@ func WithError(t *testing.T, err error) {
 	}
 }
 
+// Golden compares actual with testdata/<name>.golden.
+func Golden(t *testing.T, name string, actual string) {
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := ioutil.WriteFile(path, []byte(actual), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(expected) != actual {
+		t.Errorf("Output doesn't match %v.\nExpected:\n%v\nActual:\n%v", path, string(expected), actual)
+	}
+}
+
+// NewDocumentedArgs returns Args which has every kind of documentation.
+func NewDocumentedArgs(t *testing.T) arguments.Args {
+	args := arguments.Args{
+		Executed:    "/usr/bin/mytool",
+		HelpWidth:   -1,
+		AutoHelp:    true,
+		Description: "Copy files to a remote host.\n\nFiles are sent over SSH. Options like -v are explained below.",
+		Environment: []arguments.EnvironmentVariable{
+			{Name: "MYTOOL_HOST", Description: "default remote host."},
+		},
+		Examples: []arguments.Example{
+			{Description: "copy a file to example.com.", Command: "mytool --host example.com a.txt"},
+			{Command: "mytool --color=always -n 3 b.txt"},
+		},
+		Epilog: "Report bugs to the issue tracker.",
+	}
+	NoError(t, args.AddGroup(arguments.OptionGroup{Name: "Network", Description: "Options for connection."}))
+	NoError(t, args.AddOptions([]argumentOption.Option{
+		{LongKey: "verbose", ShortKey: "v", Description: "print verbose output."},
+		{
+			LongKey:       "color",
+			ValueType:     "string",
+			ValueName:     "WHEN",
+			ValueOptional: true,
+			ImplicitValue: "auto",
+			Description:   "colorize output.",
+			Validator:     validator.ValidateStringUseable,
+			ValidatorParam: validator.ParamString{
+				Useable: []string{"always", "auto", "never"},
+			},
+		},
+		{LongKey: "num", ShortKey: "n", ValueType: "int", Description: "number of retries.", DefaultValue: 3},
+		{LongKey: "host", LongAliases: []string{"remote"}, ValueType: "string", Description: "remote host.", Required: true, Group: "Network"},
+		{LongKey: "port", ShortKey: "p", ValueType: "int", Description: "remote port.", DefaultValue: 22, Group: "Network"},
+		{LongKey: "legacy", Deprecated: true, ReplacedBy: "--host"},
+		{LongKey: "debug-dump", Hidden: true},
+	}))
+	NoError(t, args.AddOperands([]argumentOperand.Operand{
+		{Key: "src", ValueType: "string", ValueHint: "file", Description: "file to copy.", Required: true},
+		{Key: "dst", ValueType: "string", Description: "remote path.", DefaultValue: "."},
+	}))
+	NoError(t, args.AddOption(argumentOption.Option{LongKey: "help", ShortKey: "h", Description: "show help message and exit."}))
+	return args
+}
+
 /*
  * Tests
  */
@@ -344,3 +421,1613 @@ func TestOperand(t *testing.T) {
 		WithError(t, parseErr)
 	})
 }
+
+func TestSuggestion(t *testing.T) {
+	t.Run("Unknown long key", func(t *testing.T) {
+		opts := []argumentOption.Option{
+			{LongKey: "verbose", ShortKey: "v"},
+			{LongKey: "version"},
+		}
+		var args arguments.Args
+		NoError(t, args.AddOptions(opts))
+
+		os.Args = []string{"some-program", "--verbsoe"}
+		parseErr := args.Parse()
+		WithError(t, parseErr)
+
+		var unknownErr *arguments.UnknownArgumentError
+		Match(t, true, errors.As(parseErr, &unknownErr))
+		Match(t, "--verbsoe", unknownErr.Arg)
+		Match(t, 1, len(unknownErr.Suggestions))
+		Match(t, "--verbose", unknownErr.Suggestions[0])
+		Match(t, true, strings.Contains(parseErr.Error(), "--verbsoe"))
+	})
+
+	t.Run("Nothing close", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOption(argumentOption.Option{LongKey: "verbose"}))
+
+		os.Args = []string{"some-program", "--quiet"}
+		parseErr := args.Parse()
+
+		var unknownErr *arguments.UnknownArgumentError
+		Match(t, true, errors.As(parseErr, &unknownErr))
+		Match(t, 0, len(unknownErr.Suggestions))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		args := arguments.Args{SuggestDistance: -1}
+		NoError(t, args.AddOption(argumentOption.Option{LongKey: "verbose"}))
+
+		os.Args = []string{"some-program", "--verbos"}
+		parseErr := args.Parse()
+
+		var unknownErr *arguments.UnknownArgumentError
+		Match(t, true, errors.As(parseErr, &unknownErr))
+		Match(t, 0, len(unknownErr.Suggestions))
+	})
+
+	t.Run("Operand useable values", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOperand(argumentOperand.Operand{
+			Key:            "command",
+			ValueType:      "string",
+			Validator:      validator.ValidateStringUseable,
+			ValidatorParam: validator.ParamString{Useable: []string{"build", "deploy"}},
+		}))
+
+		clone := args.Clone()
+
+		os.Args = []string{"some-program", "biuld"}
+		parseErr := args.Parse()
+
+		var useableErr *validator.UseableError
+		Match(t, true, errors.As(parseErr, &useableErr))
+		Match(t, "command", useableErr.Name)
+		Match(t, "build", useableErr.Suggestions[0])
+		Match(t, "Invalid value of command \"biuld\". Useable values are build, deploy. Did you mean \"build\"?",
+			parseErr.Error())
+
+		// The error is the same type without suggestions.
+		os.Args = []string{"some-program", "test"}
+		parseErr = clone.Parse()
+		Match(t, true, errors.As(parseErr, &useableErr))
+		Match(t, 0, len(useableErr.Suggestions))
+	})
+}
+
+func TestAbbrev(t *testing.T) {
+	opts := []argumentOption.Option{
+		{LongKey: "verbose"},
+		{LongKey: "version"},
+		{LongKey: "name", ValueType: "string"},
+		{LongKey: "names", ValueType: "string"},
+	}
+
+	t.Run("Unique prefix", func(t *testing.T) {
+		args := arguments.Args{AllowAbbrev: true}
+		NoError(t, args.AddOptions(opts))
+
+		os.Args = []string{"some-program", "--verb"}
+		NoError(t, args.Parse())
+		Match(t, true, args.OptIsSet("verbose"))
+	})
+
+	t.Run("Ambiguous prefix", func(t *testing.T) {
+		args := arguments.Args{AllowAbbrev: true}
+		NoError(t, args.AddOptions(opts))
+
+		os.Args = []string{"some-program", "--ver"}
+		parseErr := args.Parse()
+
+		var ambiguousErr *arguments.AmbiguousOptionError
+		Match(t, true, errors.As(parseErr, &ambiguousErr))
+		Match(t, 2, len(ambiguousErr.Candidates))
+	})
+
+	t.Run("Exact match wins", func(t *testing.T) {
+		args := arguments.Args{AllowAbbrev: true}
+		NoError(t, args.AddOptions(opts))
+
+		os.Args = []string{"some-program", "--name", "foo"}
+		NoError(t, args.Parse())
+		Match(t, true, args.OptIsSet("name"))
+		Match(t, false, args.OptIsSet("names"))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOptions(opts))
+
+		os.Args = []string{"some-program", "--verb"}
+		WithError(t, args.Parse())
+	})
+
+	t.Run("Empty long key", func(t *testing.T) {
+		args := arguments.Args{AllowAbbrev: true}
+		NoError(t, args.AddOption(argumentOption.Option{LongKey: "color", ValueType: "string"}))
+
+		os.Args = []string{"some-program", "--=red"}
+		WithError(t, args.Parse())
+		Match(t, false, args.OptIsSet("color"))
+
+		// "--=" is not --help either.
+		args = arguments.Args{AllowAbbrev: true, AutoHelp: true, Output: ioutil.Discard}
+		os.Args = []string{"some-program", "--="}
+		parseErr := args.Parse()
+		WithError(t, parseErr)
+		Match(t, false, errors.Is(parseErr, arguments.ErrHelp))
+	})
+}
+
+func TestAliases(t *testing.T) {
+	opt := argumentOption.Option{
+		LongKey:      "output",
+		LongAliases:  []string{"out"},
+		ShortKey:     "o",
+		ShortAliases: []string{"O"},
+		ValueType:    "string",
+	}
+
+	t.Run("Long alias", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOption(opt))
+
+		os.Args = []string{"some-program", "--out", "file"}
+		NoError(t, args.Parse())
+
+		val, getStrErr := args.GetStringOpt("output")
+		Match(t, "file", val)
+		NoError(t, getStrErr)
+	})
+
+	t.Run("Short alias", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOption(opt))
+
+		os.Args = []string{"some-program", "-O", "file"}
+		NoError(t, args.Parse())
+
+		val, getStrErr := args.GetStringOpt("o")
+		Match(t, "file", val)
+		NoError(t, getStrErr)
+	})
+
+	t.Run("Same option twice by aliases", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOption(opt))
+
+		os.Args = []string{"some-program", "--out", "a", "--output", "b"}
+		WithError(t, args.Parse())
+	})
+
+	t.Run("Alias collision", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOption(opt))
+		WithError(t, args.AddOption(argumentOption.Option{LongKey: "out"}))
+		WithError(t, args.AddOption(argumentOption.Option{
+			LongKey:      "other",
+			ShortKey:     "x",
+			ShortAliases: []string{"o"},
+		}))
+	})
+
+	t.Run("Help", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOption(opt))
+		Match(t, true, strings.Contains(args.String(), "--output|--out -o|-O string"))
+	})
+}
+
+func TestRegistration(t *testing.T) {
+	t.Run("Duplicate long key", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOption(argumentOption.Option{LongKey: "long"}))
+		WithError(t, args.AddOption(argumentOption.Option{LongKey: "long", ShortKey: "l"}))
+	})
+
+	t.Run("Duplicate short key", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOption(argumentOption.Option{ShortKey: "s"}))
+		WithError(t, args.AddOption(argumentOption.Option{LongKey: "long", ShortKey: "s"}))
+	})
+
+	t.Run("Duplicate operand key", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOperand(argumentOperand.Operand{Key: "src", ValueType: "string"}))
+		WithError(t, args.AddOperand(argumentOperand.Operand{Key: "src", ValueType: "int"}))
+	})
+
+	t.Run("Invalid key syntax", func(t *testing.T) {
+		var args arguments.Args
+		WithError(t, args.AddOption(argumentOption.Option{LongKey: "--long"}))
+		WithError(t, args.AddOption(argumentOption.Option{LongKey: "l"}))
+		WithError(t, args.AddOption(argumentOption.Option{ShortKey: "-s"}))
+		WithError(t, args.AddOption(argumentOption.Option{ShortKey: "ss"}))
+		WithError(t, args.AddOption(argumentOption.Option{LongKey: "with space"}))
+		WithError(t, args.AddOperand(argumentOperand.Operand{Key: "-src", ValueType: "string"}))
+		NoError(t, args.AddOption(argumentOption.Option{LongKey: "dry-run", ShortKey: "n"}))
+	})
+
+	t.Run("Default value type", func(t *testing.T) {
+		var args arguments.Args
+		WithError(t, args.AddOption(argumentOption.Option{
+			LongKey: "int", ValueType: "int", DefaultValue: "10",
+		}))
+		WithError(t, args.AddOption(argumentOption.Option{
+			LongKey: "flag", DefaultValue: true,
+		}))
+		WithError(t, args.AddOption(argumentOption.Option{
+			LongKey: "float", ValueType: "float",
+		}))
+		WithError(t, args.AddOperand(argumentOperand.Operand{
+			Key: "src", ValueType: "string", DefaultValue: 1,
+		}))
+		NoError(t, args.AddOption(argumentOption.Option{
+			LongKey: "int", ValueType: "int", DefaultValue: 10,
+		}))
+	})
+}
+
+func TestOptionalValue(t *testing.T) {
+	opt := argumentOption.Option{
+		LongKey:       "color",
+		ShortKey:      "c",
+		ValueType:     "string",
+		ValueName:     "WHEN",
+		ValueOptional: true,
+		ImplicitValue: "auto",
+		DefaultValue:  "never",
+	}
+	ope := argumentOperand.Operand{
+		Key:       "file",
+		ValueType: "string",
+	}
+
+	t.Run("Bare", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOption(opt))
+		NoError(t, args.AddOperand(ope))
+
+		os.Args = []string{"some-program", "--color", "always"}
+		NoError(t, args.Parse())
+
+		color, getStrErr := args.GetStringOpt("color")
+		Match(t, "auto", color)
+		NoError(t, getStrErr)
+		file, getStrErr := args.GetStringOperand("file")
+		Match(t, "always", file)
+		NoError(t, getStrErr)
+	})
+
+	t.Run("Attached", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOption(opt))
+
+		os.Args = []string{"some-program", "--color=always"}
+		NoError(t, args.Parse())
+
+		color, getStrErr := args.GetStringOpt("c")
+		Match(t, "always", color)
+		NoError(t, getStrErr)
+	})
+
+	t.Run("Not specified", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOption(opt))
+
+		os.Args = []string{"some-program"}
+		NoError(t, args.Parse())
+
+		color, getStrErr := args.GetStringOpt("color")
+		Match(t, "never", color)
+		NoError(t, getStrErr)
+	})
+
+	t.Run("Not specified enum", func(t *testing.T) {
+		args := NewDocumentedArgs(t)
+		NoError(t, args.AddOperand(argumentOperand.Operand{
+			Key:            "mode",
+			ValueType:      "int",
+			Validator:      validator.ValidateIntUseable,
+			ValidatorParam: validator.ParamInt{Useable: []int{1, 2}},
+		}))
+
+		os.Args = []string{"some-program", "--host", "example.com", "a.txt", "b"}
+		NoError(t, args.Parse())
+		Match(t, false, args.OptIsSet("color"))
+	})
+
+	t.Run("Attached value for required value", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOption(argumentOption.Option{LongKey: "num", ValueType: "int"}))
+
+		os.Args = []string{"some-program", "--num=10"}
+		NoError(t, args.Parse())
+
+		num, getIntErr := args.GetIntOpt("num")
+		Match(t, 10, num)
+		NoError(t, getIntErr)
+	})
+
+	t.Run("Attached value for flag", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOption(argumentOption.Option{LongKey: "flag"}))
+
+		os.Args = []string{"some-program", "--flag=true"}
+		WithError(t, args.Parse())
+	})
+
+	t.Run("Without implicit value", func(t *testing.T) {
+		var args arguments.Args
+		noImplicit := opt
+		noImplicit.ImplicitValue = nil
+		WithError(t, args.AddOption(noImplicit))
+	})
+
+	t.Run("Help", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOption(opt))
+		Match(t, true, strings.Contains(args.String(), "--color[=WHEN] -c"))
+	})
+}
+
+func TestAutoHelp(t *testing.T) {
+	opts := []argumentOption.Option{
+		{LongKey: "name", ValueType: "string", Required: true},
+	}
+
+	t.Run("Help", func(t *testing.T) {
+		var output bytes.Buffer
+		args := arguments.Args{AutoHelp: true, Output: &output}
+		NoError(t, args.AddOptions(opts))
+
+		os.Args = []string{"some-program", "-h"}
+		parseErr := args.Parse()
+		Match(t, arguments.ErrHelp, parseErr)
+		Match(t, true, strings.Contains(output.String(), "--help -h"))
+		Match(t, true, strings.Contains(output.String(), "--name string"))
+	})
+
+	t.Run("Help with invalid arguments", func(t *testing.T) {
+		var output bytes.Buffer
+		args := arguments.Args{AutoHelp: true, Output: &output}
+		NoError(t, args.AddOptions(opts))
+
+		os.Args = []string{"some-program", "--unknown", "--help"}
+		Match(t, arguments.ErrHelp, args.Parse())
+	})
+
+	t.Run("Short key already used", func(t *testing.T) {
+		var output bytes.Buffer
+		args := arguments.Args{AutoHelp: true, Output: &output}
+		NoError(t, args.AddOption(argumentOption.Option{LongKey: "host", ShortKey: "h", ValueType: "string"}))
+
+		os.Args = []string{"some-program", "-h", "localhost"}
+		NoError(t, args.Parse())
+		Match(t, 0, output.Len())
+	})
+
+	t.Run("Not specified", func(t *testing.T) {
+		var output bytes.Buffer
+		args := arguments.Args{AutoHelp: true, Output: &output}
+		NoError(t, args.AddOptions(opts))
+
+		os.Args = []string{"some-program"}
+		parseErr := args.Parse()
+		WithError(t, parseErr)
+		Match(t, false, errors.Is(parseErr, arguments.ErrHelp))
+		Match(t, 0, output.Len())
+	})
+}
+
+func TestAutoVersion(t *testing.T) {
+	opts := []argumentOption.Option{
+		{LongKey: "name", ValueType: "string", Required: true},
+	}
+
+	t.Run("Text", func(t *testing.T) {
+		var output bytes.Buffer
+		args := arguments.Args{AutoVersion: true, Version: "v1.2.3", Output: &output}
+		NoError(t, args.AddOptions(opts))
+
+		os.Args = []string{"/usr/bin/some-program", "--version"}
+		Match(t, arguments.ErrVersion, args.Parse())
+		Match(t, true, strings.HasPrefix(output.String(), "some-program v1.2.3"))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var output bytes.Buffer
+		args := arguments.Args{AutoVersion: true, Version: "v1.2.3", Output: &output}
+		NoError(t, args.AddOptions(opts))
+
+		os.Args = []string{"some-program", "--version=json"}
+		Match(t, arguments.ErrVersion, args.Parse())
+
+		var info arguments.VersionInfo
+		NoError(t, json.Unmarshal(output.Bytes(), &info))
+		Match(t, "v1.2.3", info.Version)
+		Match(t, "some-program", info.Program)
+	})
+
+	t.Run("Unknown format", func(t *testing.T) {
+		var output bytes.Buffer
+		args := arguments.Args{AutoVersion: true, Output: &output}
+
+		os.Args = []string{"some-program", "--version=jsn"}
+		parseErr := args.Parse()
+
+		var unknownErr *arguments.UnknownArgumentError
+		Match(t, true, errors.As(parseErr, &unknownErr))
+		Match(t, "json", unknownErr.Suggestions[0])
+	})
+
+	t.Run("Build info", func(t *testing.T) {
+		var args arguments.Args
+		Match(t, false, args.GetVersionInfo().GoVersion == "")
+	})
+}
+
+func TestSynopsis(t *testing.T) {
+	opts := []argumentOption.Option{
+		{LongKey: "verbose", ShortKey: "v"},
+		{LongKey: "name", ValueType: "string", Required: true},
+		{LongKey: "int", ShortKey: "i", ValueType: "int"},
+		{LongKey: "color", ValueType: "string", ValueOptional: true, ImplicitValue: "auto"},
+	}
+	opes := []argumentOperand.Operand{
+		{Key: "src", ValueType: "string", Required: true},
+		{Key: "dst", ValueType: "string"},
+	}
+
+	t.Run("Options and operands", func(t *testing.T) {
+		args := arguments.Args{Executed: "/usr/local/bin/prog"}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperands(opes))
+		Match(t,
+			"prog [-v] --name string [-i int] [--color[=string]] <src> [dst]",
+			args.Synopsis())
+	})
+
+	t.Run("Collapse", func(t *testing.T) {
+		args := arguments.Args{Executed: "prog", SynopsisMaxOptions: 2}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperands(opes))
+		Match(t, "prog [options] --name string <src> [dst]", args.Synopsis())
+	})
+
+	t.Run("Hidden and deprecated", func(t *testing.T) {
+		args := arguments.Args{Executed: "prog"}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOptions([]argumentOption.Option{
+			{LongKey: "debug", Hidden: true},
+			{LongKey: "legacy", Deprecated: true},
+		}))
+		NoError(t, args.AddOperands(opes))
+		NoError(t, args.AddOperands([]argumentOperand.Operand{
+			{Key: "mode", ValueType: "string", Hidden: true},
+			{Key: "old", ValueType: "string", Deprecated: true},
+		}))
+		Match(t,
+			"prog [-v] --name string [-i int] [--color[=string]] <src> [dst]",
+			args.Synopsis())
+	})
+
+	t.Run("Nothing registered", func(t *testing.T) {
+		args := arguments.Args{Executed: "./prog"}
+		Match(t, "prog", args.Synopsis())
+	})
+}
+
+func TestHelpWidth(t *testing.T) {
+	opts := []argumentOption.Option{
+		{
+			LongKey:      "int",
+			ShortKey:     "i",
+			ValueType:    "int",
+			Description:  "some option with a long description which doesn't fit in a narrow terminal.",
+			DefaultValue: 10,
+		},
+		{
+			LongKey:     "name",
+			ValueType:   "string",
+			Description: "名前を指定します。 表示の幅は 全角文字 ごとに 2 桁です。",
+			Required:    true,
+		},
+	}
+
+	t.Run("Wrap", func(t *testing.T) {
+		args := arguments.Args{Executed: "prog", HelpWidth: 50}
+		NoError(t, args.AddOptions(opts))
+
+		help := args.String()
+		for _, line := range strings.Split(help, "\n") {
+			if helpFormatter.StringWidth(line) > 50 {
+				t.Errorf("Line is wider than 50: %v", line)
+			}
+		}
+		Match(t, true, strings.Contains(help, "(default: 10)"))
+		Match(t, true, strings.Contains(help, "\n                    description which"))
+	})
+
+	t.Run("No wrap", func(t *testing.T) {
+		args := arguments.Args{Executed: "prog", HelpWidth: -1}
+		NoError(t, args.AddOptions(opts))
+
+		help := args.String()
+		Match(t, true, strings.Contains(help,
+			"    --int -i int  : some option with a long description which doesn't fit in a narrow terminal. (default: 10)\n"))
+	})
+}
+
+func TestHelpTemplate(t *testing.T) {
+	opts := []argumentOption.Option{
+		{LongKey: "int", ShortKey: "i", ValueType: "int", Description: "some option.", DefaultValue: 10},
+		{LongKey: "name", ValueType: "string", Description: "some name.", Required: true},
+	}
+	opes := []argumentOperand.Operand{
+		{Key: "src", ValueType: "string", Description: "some operand."},
+	}
+
+	t.Run("Default template", func(t *testing.T) {
+		args := arguments.Args{Executed: "prog", HelpWidth: -1}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperands(opes))
+		Match(t, `
+Usage:
+  prog [-i int] --name string [src]
+
+  Options
+    --int -i int  : some option. (default: 10)
+    --name string : some name. (required)
+
+  Operands
+    src (string) : some operand.
+`, args.String())
+	})
+
+	t.Run("Description, examples and epilog", func(t *testing.T) {
+		args := arguments.Args{
+			Executed:    "prog",
+			HelpWidth:   -1,
+			Description: "Some program.",
+			Examples:    []arguments.Example{{Description: "some example.", Command: "prog --name foo"}},
+			Epilog:      "Some epilog.",
+		}
+		NoError(t, args.AddOptions(opts))
+		Match(t, `
+Usage:
+  prog [-i int] --name string
+
+  Some program.
+
+  Options
+    --int -i int  : some option. (default: 10)
+    --name string : some name. (required)
+
+  Examples
+    # some example.
+    $ prog --name foo
+
+Some epilog.
+`, args.String())
+	})
+
+	t.Run("Documented", func(t *testing.T) {
+		args := NewDocumentedArgs(t)
+		Golden(t, "help_documented", args.String())
+	})
+
+	t.Run("Custom template", func(t *testing.T) {
+		args := arguments.Args{
+			Executed:     "prog",
+			HelpTemplate: "{{.Name}}{{range .Options}} {{index .LongKeys 0}}={{.Default}}{{end}}",
+		}
+		NoError(t, args.AddOptions(opts))
+		Match(t, "prog int=10 name=", args.String())
+	})
+
+	t.Run("Invalid template", func(t *testing.T) {
+		args := arguments.Args{HelpTemplate: "{{.Unknown}}"}
+		var output bytes.Buffer
+		WithError(t, args.WriteHelp(&output))
+	})
+}
+
+func TestGroups(t *testing.T) {
+	opts := []argumentOption.Option{
+		{LongKey: "verbose", ShortKey: "v", Description: "verbose output."},
+		{LongKey: "host", ValueType: "string", Description: "host name.", Group: "Network"},
+		{LongKey: "dry-run", Description: "don't write files.", Group: "Debug"},
+		{LongKey: "port", ValueType: "int", Description: "port number.", Group: "Network"},
+	}
+
+	t.Run("Sections", func(t *testing.T) {
+		args := arguments.Args{Executed: "prog", HelpWidth: -1}
+		NoError(t, args.AddGroups([]arguments.OptionGroup{
+			{Name: "Network", Description: "Options for connection."},
+		}))
+		NoError(t, args.AddOptions(opts))
+		Match(t, `
+Usage:
+  prog [-v] [--host string] [--dry-run] [--port int]
+
+  Options
+    --verbose -v : verbose output.
+
+  Network
+    Options for connection.
+    --host string : host name.
+    --port int    : port number.
+
+  Debug
+    --dry-run : don't write files.
+
+`, args.String())
+	})
+
+	t.Run("Duplicate group", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddGroup(arguments.OptionGroup{Name: "Network"}))
+		WithError(t, args.AddGroup(arguments.OptionGroup{Name: "Network"}))
+		WithError(t, args.AddGroup(arguments.OptionGroup{}))
+	})
+
+	t.Run("Template data", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOptions(opts))
+		data := args.HelpData()
+		Match(t, 3, len(data.Groups))
+		Match(t, 4, len(data.Options))
+		Match(t, "Debug", data.Groups[2].Name)
+	})
+}
+
+func TestHiddenAndDeprecated(t *testing.T) {
+	opts := []argumentOption.Option{
+		{LongKey: "output", ShortKey: "o", ValueType: "string", Description: "output file."},
+		{LongKey: "out", ValueType: "string", Deprecated: true, ReplacedBy: "--output"},
+		{LongKey: "debug-dump", Hidden: true},
+		{
+			LongKey:      "format",
+			ValueType:    "string",
+			DefaultValue: "json",
+			Validator:    validator.ValidateStringUseable,
+			ValidatorParam: validator.ParamString{
+				Useable:           []string{"json", "text"},
+				HiddenUseable:     []string{"raw"},
+				DeprecatedUseable: map[string]string{"yml": "yaml"},
+			},
+		},
+	}
+
+	t.Run("Hidden option", func(t *testing.T) {
+		args := arguments.Args{Executed: "prog"}
+		NoError(t, args.AddOptions(opts))
+
+		os.Args = []string{"some-program", "--debug-dump"}
+		NoError(t, args.Parse())
+		Match(t, true, args.OptIsSet("debug-dump"))
+		Match(t, false, strings.Contains(args.String(), "debug-dump"))
+	})
+
+	t.Run("Deprecated option", func(t *testing.T) {
+		var warnings []string
+		args := arguments.Args{
+			WarningHandler: func(warning string) { warnings = append(warnings, warning) },
+		}
+		NoError(t, args.AddOptions(opts))
+
+		os.Args = []string{"some-program", "--out", "file"}
+		NoError(t, args.Parse())
+		Match(t, 1, len(warnings))
+		Match(t, "Option --out is deprecated. Use --output instead.", warnings[0])
+		Match(t, true, strings.Contains(args.String(), "(deprecated, use --output)"))
+		Match(t, false, strings.Contains(args.Synopsis(), "--out "))
+	})
+
+	t.Run("Hidden and deprecated values", func(t *testing.T) {
+		var warnings []string
+		args := arguments.Args{
+			WarningHandler: func(warning string) { warnings = append(warnings, warning) },
+		}
+		NoError(t, args.AddOptions(opts))
+
+		os.Args = []string{"some-program", "--format", "raw"}
+		NoError(t, args.Parse())
+		Match(t, 0, len(warnings))
+
+		args = arguments.Args{
+			WarningHandler: func(warning string) { warnings = append(warnings, warning) },
+		}
+		NoError(t, args.AddOptions(opts))
+		os.Args = []string{"some-program", "--format", "yml"}
+		NoError(t, args.Parse())
+		Match(t, 1, len(warnings))
+		Match(t, true, strings.Contains(warnings[0], "Use \"yaml\" instead."))
+	})
+
+	t.Run("Deprecated values without replacement", func(t *testing.T) {
+		var warnings []string
+		args := arguments.Args{
+			WarningHandler: func(warning string) { warnings = append(warnings, warning) },
+		}
+		NoError(t, args.AddOptions([]argumentOption.Option{
+			{
+				LongKey:   "level",
+				ValueType: "int",
+				Validator: validator.ValidateIntUseable,
+				ValidatorParam: validator.ParamInt{
+					Useable:           []int{1, 2},
+					DeprecatedUseable: map[int]string{0: "", 9: "2"},
+				},
+			},
+			{
+				LongKey:   "mode",
+				ValueType: "string",
+				Validator: validator.ValidateStringUseable,
+				ValidatorParam: validator.ParamString{
+					Useable:           []string{"fast"},
+					DeprecatedUseable: map[string]string{"legacy": ""},
+				},
+			},
+		}))
+
+		definition := args.Clone()
+		os.Args = []string{"some-program", "--level", "0", "--mode", "legacy"}
+		NoError(t, args.Parse())
+		Match(t, "Value \"0\" of --level is deprecated.|Value \"legacy\" of --mode is deprecated.",
+			strings.Join(warnings, "|"))
+
+		warnings = nil
+		args = definition.Clone()
+		NoError(t, args.ParseArgs([]string{"some-program", "--level", "9", "--mode", "fast"}))
+		Match(t, "Value \"9\" of --level is deprecated. Use \"2\" instead.", strings.Join(warnings, "|"))
+	})
+
+	t.Run("Hidden values are not listed", func(t *testing.T) {
+		args := arguments.Args{SuggestDistance: -1}
+		NoError(t, args.AddOptions(opts))
+
+		os.Args = []string{"some-program", "--format", "xml"}
+		parseErr := args.Parse()
+		WithError(t, parseErr)
+		Match(t, false, strings.Contains(parseErr.Error(), "raw"))
+	})
+
+	t.Run("Hidden operand", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOperands([]argumentOperand.Operand{
+			{Key: "src", ValueType: "string"},
+			{Key: "legacy", ValueType: "string", Hidden: true},
+		}))
+
+		os.Args = []string{"some-program", "a", "b"}
+		NoError(t, args.Parse())
+		Match(t, "some-program [src]", args.Synopsis())
+		Match(t, false, strings.Contains(args.String(), "legacy"))
+	})
+}
+
+func TestManPage(t *testing.T) {
+	t.Run("Documented", func(t *testing.T) {
+		args := NewDocumentedArgs(t)
+		var output bytes.Buffer
+		NoError(t, args.WriteManPage(&output, arguments.ManHeader{
+			Date:   "2020-02-22",
+			Source: "mytool 1.0.0",
+			Manual: "User Commands",
+		}))
+		Golden(t, "man_documented", output.String())
+	})
+
+	t.Run("Minimal", func(t *testing.T) {
+		args := arguments.Args{Executed: "minimal"}
+		NoError(t, args.AddOption(argumentOption.Option{ShortKey: "q"}))
+		var output bytes.Buffer
+		NoError(t, args.WriteManPage(&output, arguments.ManHeader{}))
+		Golden(t, "man_minimal", output.String())
+	})
+}
+
+func TestReferenceDocs(t *testing.T) {
+	t.Run("Markdown", func(t *testing.T) {
+		args := NewDocumentedArgs(t)
+		var output bytes.Buffer
+		NoError(t, args.WriteMarkdown(&output))
+		Golden(t, "reference.md", output.String())
+	})
+
+	t.Run("HTML", func(t *testing.T) {
+		args := NewDocumentedArgs(t)
+		var output bytes.Buffer
+		NoError(t, args.WriteHTML(&output))
+		Golden(t, "reference.html", output.String())
+	})
+
+	t.Run("Escape", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOption(argumentOption.Option{LongKey: "pipe", Description: "a|b <b>"}))
+
+		var markdown bytes.Buffer
+		NoError(t, args.WriteMarkdown(&markdown))
+		Match(t, true, strings.Contains(markdown.String(), `a\|b <b>`))
+
+		var html bytes.Buffer
+		NoError(t, args.WriteHTML(&html))
+		Match(t, true, strings.Contains(html.String(), "a|b &lt;b&gt;"))
+	})
+}
+
+func TestCompletion(t *testing.T) {
+	opts := []argumentOption.Option{
+		{LongKey: "config", ShortKey: "C", ValueType: "string", ValueHint: "file", Description: "config file."},
+		{LongKey: "workdir", ValueType: "string", ValueHint: "dir", Description: "working directory."},
+		{
+			LongKey:      "format",
+			ShortKey:     "f",
+			ValueType:    "string",
+			DefaultValue: "json",
+			Description:  "output format.",
+			Validator:    validator.ValidateStringUseable,
+			ValidatorParam: validator.ParamString{
+				Useable: []string{"json", "text"},
+			},
+		},
+	}
+
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		shell := shell
+		t.Run(shell, func(t *testing.T) {
+			args := NewDocumentedArgs(t)
+			NoError(t, args.AddOptions(opts))
+			var output bytes.Buffer
+			NoError(t, args.WriteCompletion(&output, shell))
+			Golden(t, "completion."+shell, output.String())
+		})
+	}
+
+	t.Run("Unknown shell", func(t *testing.T) {
+		args := NewDocumentedArgs(t)
+		NoError(t, args.AddOptions(opts))
+		var output bytes.Buffer
+		err := args.WriteCompletion(&output, "bahs")
+		var unknownErr *arguments.UnknownArgumentError
+		Match(t, true, errors.As(err, &unknownErr))
+		Match(t, "Invalid value of shell \"bahs\". Did you mean \"bash\"?", err.Error())
+	})
+
+	t.Run("Unknown hint", func(t *testing.T) {
+		var args arguments.Args
+		WithError(t, args.AddOption(argumentOption.Option{LongKey: "out", ValueType: "string", ValueHint: "path"}))
+		WithError(t, args.AddOption(argumentOption.Option{LongKey: "flag", ValueHint: "file"}))
+		WithError(t, args.AddOperand(argumentOperand.Operand{Key: "src", ValueType: "string", ValueHint: "path"}))
+	})
+}
+
+func TestDynamicCompletion(t *testing.T) {
+	var completedArgs []string
+	branches := func(args []string, toComplete string) ([]completion.Candidate, completion.Directive) {
+		completedArgs = args
+		candidates := []completion.Candidate{
+			{Value: "main", Description: "default branch"},
+			{Value: "master"},
+			{Value: "develop"},
+		}
+		return completion.Filter(candidates, toComplete), completion.NoFileComp | completion.NoSpace
+	}
+	opts := []argumentOption.Option{
+		{LongKey: "branch", ShortKey: "b", ValueType: "string", Description: "branch to deploy.", Complete: branches},
+		{LongKey: "verbose", ShortKey: "v", Description: "verbose output."},
+		{
+			LongKey:      "format",
+			ValueType:    "string",
+			DefaultValue: "json",
+			Validator:    validator.ValidateStringUseable,
+			ValidatorParam: validator.ParamString{
+				Useable: []string{"json", "text"},
+			},
+		},
+		{LongKey: "workdir", ValueType: "string", ValueHint: "dir"},
+	}
+	opes := []argumentOperand.Operand{
+		{Key: "src", ValueType: "string", ValueHint: "file", Required: true},
+		{Key: "tag", ValueType: "string", Complete: branches},
+	}
+	complete := func(t *testing.T, words ...string) string {
+		var output bytes.Buffer
+		args := arguments.Args{AutoHelp: true, Output: &output}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperands(opes))
+		os.Args = append([]string{"mytool", arguments.CompleteCommand}, words...)
+		Match(t, arguments.ErrComplete, args.Parse())
+		return output.String()
+	}
+
+	t.Run("Option value", func(t *testing.T) {
+		Match(t, "main\tdefault branch\nmaster\n:6\n", complete(t, "-v", "--branch", "ma"))
+		Match(t, "-v,--branch", strings.Join(completedArgs, ","))
+	})
+
+	t.Run("Attached option value", func(t *testing.T) {
+		Match(t, "develop\n:6\n", complete(t, "--branch=d"))
+		Match(t, "text\n:4\n", complete(t, "--format=t"))
+	})
+
+	t.Run("Option keys", func(t *testing.T) {
+		Match(t, "--verbose\tverbose output.\n:4\n", complete(t, "--verb"))
+	})
+
+	t.Run("Operands", func(t *testing.T) {
+		Match(t, ":0\n", complete(t, ""))
+		Match(t, "main\tdefault branch\nmaster\ndevelop\n:6\n", complete(t, "--workdir", "/tmp", "a.txt", ""))
+		Match(t, ":4\n", complete(t, "a.txt", "main", ""))
+	})
+
+	t.Run("Hints", func(t *testing.T) {
+		Match(t, ":8\n", complete(t, "--workdir", ""))
+	})
+
+	t.Run("Unknown option", func(t *testing.T) {
+		Match(t, ":1\n", complete(t, "--unknown=x"))
+	})
+
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		shell := shell
+		t.Run(shell, func(t *testing.T) {
+			args := arguments.Args{Executed: "mytool", AutoHelp: true}
+			NoError(t, args.AddOptions(opts))
+			NoError(t, args.AddOperands(opes))
+			var output bytes.Buffer
+			NoError(t, args.WriteCompletion(&output, shell))
+			Golden(t, "completion_dynamic."+shell, output.String())
+		})
+	}
+}
+
+func TestResponseFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "arguments")
+	NoError(t, err)
+	defer os.RemoveAll(dir)
+	write := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	opts := []argumentOption.Option{
+		{LongKey: "name", ShortKey: "n", ValueType: "string"},
+		{LongKey: "verbose", ShortKey: "v"},
+	}
+	opes := []argumentOperand.Operand{
+		{Key: "src", ValueType: "string"},
+		{Key: "dst", ValueType: "string"},
+	}
+
+	t.Run("Expand", func(t *testing.T) {
+		write("nested.txt", "'dst file'\n")
+		path := write("args.txt", "# options\n--name \"some name\" # comment\n\n-v @nested.txt\n")
+		args := arguments.Args{ResponseFiles: true}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperands(opes))
+		os.Args = []string{"some-program", "src", "@" + path}
+		NoError(t, args.Parse())
+		name, err := args.GetStringOpt("--name")
+		NoError(t, err)
+		Match(t, "some name", name)
+		Match(t, true, args.OptIsSet("-v"))
+		dst, err := args.GetStringOperand("dst")
+		NoError(t, err)
+		Match(t, "dst file", dst)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperands(opes))
+		os.Args = []string{"some-program", "@args.txt"}
+		NoError(t, args.Parse())
+		src, err := args.GetStringOperand("src")
+		NoError(t, err)
+		Match(t, "@args.txt", src)
+	})
+
+	t.Run("Syntax error", func(t *testing.T) {
+		path := write("bad.txt", "--name\n\"unterminated\n")
+		args := arguments.Args{ResponseFiles: true}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperands(opes))
+		os.Args = []string{"some-program", "@" + path}
+		err := args.Parse()
+		var fileErr *arguments.ResponseFileError
+		Match(t, true, errors.As(err, &fileErr))
+		Match(t, path, fileErr.Path)
+		Match(t, 2, fileErr.Line)
+		Match(t, path+":2: Unterminated double quote.", err.Error())
+	})
+
+	t.Run("Missing nested file", func(t *testing.T) {
+		path := write("missing.txt", "-v\n@not-found.txt\n")
+		args := arguments.Args{ResponseFiles: true}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperands(opes))
+		os.Args = []string{"some-program", "@" + path}
+		err := args.Parse()
+		var fileErr *arguments.ResponseFileError
+		Match(t, true, errors.As(err, &fileErr))
+		Match(t, path, fileErr.Path)
+		Match(t, 2, fileErr.Line)
+		Match(t, true, os.IsNotExist(errors.Unwrap(err)))
+	})
+
+	t.Run("Parse error", func(t *testing.T) {
+		path := write("unknown.txt", "-v\n--bad\n")
+		args := arguments.Args{ResponseFiles: true}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperands(opes))
+		os.Args = []string{"some-program", "@" + path}
+		err := args.Parse()
+		var fileErr *arguments.ResponseFileError
+		Match(t, true, errors.As(err, &fileErr))
+		Match(t, 2, fileErr.Line)
+		Match(t, path+":2: Unknown option \"--bad\".", err.Error())
+		var unknownErr *arguments.UnknownArgumentError
+		Match(t, true, errors.As(err, &unknownErr))
+
+		// Errors of arguments in argv have no position.
+		args = arguments.Args{ResponseFiles: true}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperands(opes))
+		os.Args = []string{"some-program", "--bad"}
+		Match(t, "Unknown option \"--bad\".", args.Parse().Error())
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		write("b.txt", "-v\n@a.txt\n")
+		path := write("a.txt", "@b.txt\n")
+		args := arguments.Args{ResponseFiles: true}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperands(opes))
+		os.Args = []string{"some-program", "@" + path}
+		err := args.Parse()
+		var fileErr *arguments.ResponseFileError
+		Match(t, true, errors.As(err, &fileErr))
+		Match(t, filepath.Join(dir, "b.txt"), fileErr.Path)
+		Match(t, 2, fileErr.Line)
+	})
+}
+
+func TestParseString(t *testing.T) {
+	opts := []argumentOption.Option{
+		{LongKey: "env", ValueType: "string"},
+		{ShortKey: "n", ValueType: "int"},
+	}
+	ope := argumentOperand.Operand{Key: "target", ValueType: "string"}
+
+	t.Run("Parse", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperand(ope))
+		NoError(t, args.ParseString(`deploy --env "prod eu" -n 3 'web server'`))
+		env, err := args.GetStringOpt("--env")
+		NoError(t, err)
+		Match(t, "prod eu", env)
+		n, err := args.GetIntOpt("-n")
+		NoError(t, err)
+		Match(t, 3, n)
+		target, err := args.GetStringOperand("target")
+		NoError(t, err)
+		Match(t, "web server", target)
+		Match(t, "deploy", args.Executed)
+	})
+
+	t.Run("ExpandEnv", func(t *testing.T) {
+		os.Setenv("ARGUMENTS_TEST_ENV", "prod eu")
+		defer os.Unsetenv("ARGUMENTS_TEST_ENV")
+		args := arguments.Args{ExpandEnv: true}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperand(ope))
+		NoError(t, args.ParseString(`deploy --env $ARGUMENTS_TEST_ENV`))
+		env, err := args.GetStringOpt("--env")
+		NoError(t, err)
+		Match(t, "prod eu", env)
+	})
+
+	t.Run("Unset variable", func(t *testing.T) {
+		os.Unsetenv("ARGUMENTS_TEST_UNSET")
+		args := arguments.Args{ExpandEnv: true}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperand(ope))
+		NoError(t, args.ParseString(`p $ARGUMENTS_TEST_UNSET`))
+		Match(t, false, args.OperandIsSet("target"))
+
+		args = arguments.Args{ExpandEnv: true}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperand(ope))
+		NoError(t, args.ParseString(`p "$ARGUMENTS_TEST_UNSET"`))
+		Match(t, true, args.OperandIsSet("target"))
+		target, err := args.GetStringOperand("target")
+		NoError(t, err)
+		Match(t, "", target)
+	})
+
+	t.Run("Unterminated quote", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperand(ope))
+		err := args.ParseString(`deploy --env "prod eu -n 3`)
+		Match(t, "line 1, column 14: Unterminated double quote.", err.Error())
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperand(ope))
+		WithError(t, args.ParseString("  "))
+	})
+}
+
+func TestREPL(t *testing.T) {
+	opts := []argumentOption.Option{
+		{LongKey: "env", ValueType: "string", Description: "environment."},
+		{LongKey: "verbose", ShortKey: "v", Description: "verbose output."},
+	}
+	ope := argumentOperand.Operand{
+		Key:       "target",
+		ValueType: "string",
+		Validator: validator.ValidateStringUseable,
+		ValidatorParam: validator.ParamString{
+			Useable: []string{"web", "db"},
+		},
+	}
+	var handled []string
+	handler := func(args *arguments.Args) error {
+		env, _ := args.GetStringOpt("--env")
+		target, _ := args.GetStringOperand("target")
+		handled = append(handled, fmt.Sprintf("env=%v verbose=%v target=%v", env, args.OptIsSet("-v"), target))
+		if target == "db" {
+			return errors.New("db is locked.")
+		}
+		return nil
+	}
+
+	t.Run("Run", func(t *testing.T) {
+		handled = nil
+		var output bytes.Buffer
+		args := arguments.Args{Executed: "console", AutoHelp: true, HelpWidth: -1}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperand(ope))
+		repl := arguments.REPL{
+			Args:    args,
+			Prompt:  "$ ",
+			Input:   strings.NewReader("--env \"prod eu\" -v web\n\n# comment\nweb\n--unknown\n'db\n--env dev db\nhistory\n!1\nexit\nweb\n"),
+			Output:  &output,
+			Handler: handler,
+		}
+		NoError(t, repl.Run())
+		// Values of a line don't remain in the next line.
+		Match(t, "env=prod eu verbose=true target=web|env= verbose=false target=web|env=dev verbose=false target=db|env=prod eu verbose=true target=web",
+			strings.Join(handled, "|"))
+		Golden(t, "repl", output.String())
+		Match(t, 9, len(repl.History))
+	})
+
+	t.Run("Help", func(t *testing.T) {
+		handled = nil
+		var output bytes.Buffer
+		args := arguments.Args{Executed: "console", AutoHelp: true, HelpWidth: -1}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperand(ope))
+		repl := arguments.REPL{Args: args, Input: strings.NewReader("--help\nhelp\n"), Output: &output, Handler: handler}
+		NoError(t, repl.Run())
+		Match(t, 0, len(handled))
+		Match(t, 2, strings.Count(output.String(), "console [--env string] [-v] [-h] [target]"))
+	})
+
+	t.Run("HistorySize", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperand(ope))
+		repl := arguments.REPL{Args: args, Output: &bytes.Buffer{}, HistorySize: 2}
+		for _, line := range []string{"web", "-v web", "--env dev web"} {
+			NoError(t, repl.Execute(line))
+		}
+		Match(t, "-v web|--env dev web", strings.Join(repl.History, "|"))
+		WithError(t, repl.Execute("!3"))
+	})
+
+	t.Run("Sensitive", func(t *testing.T) {
+		var output bytes.Buffer
+		var args arguments.Args
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperand(ope))
+		NoError(t, args.AddOption(argumentOption.Option{LongKey: "token", ValueType: "string", Sensitive: true}))
+		var tokens []string
+		repl := arguments.REPL{
+			Args:   args,
+			Input:  strings.NewReader("--token xyz web\n--token=\"x y\" --env 'prod eu' web\n!!\n!1\nhistory\n"),
+			Output: &output,
+			Handler: func(args *arguments.Args) error {
+				token, _ := args.GetSecretOpt("--token")
+				tokens = append(tokens, token)
+				return nil
+			},
+		}
+		NoError(t, repl.Run())
+		// Redacted values are not given to Handler by recalled lines.
+		Match(t, "xyz|x y", strings.Join(tokens, "|"))
+		Match(t, 2, strings.Count(output.String(), "Error: The value of --token is redacted in history."))
+		Match(t, "--token '[redacted]' web|'--token=[redacted]' --env 'prod eu' web|history",
+			strings.Join(repl.History, "|"))
+		Match(t, false, strings.Contains(output.String(), "xyz"))
+		Match(t, false, strings.Contains(output.String(), "x y"))
+		// Lines without sensitive values are kept as they are typed.
+		NoError(t, repl.Execute("--env \"dev\"   web"))
+		Match(t, "--env \"dev\"   web", repl.History[len(repl.History)-1])
+		// Lines without redacted values can be recalled.
+		NoError(t, repl.Execute("!!"))
+	})
+
+	t.Run("Complete", func(t *testing.T) {
+		args := arguments.Args{AutoHelp: true}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperand(ope))
+		repl := arguments.REPL{Args: args}
+		values := func(candidates []completion.Candidate) string {
+			var strs []string
+			for _, candidate := range candidates {
+				strs = append(strs, candidate.Value)
+			}
+			return strings.Join(strs, " ")
+		}
+		candidates, _ := repl.Complete("--v")
+		Match(t, "--verbose", values(candidates))
+		candidates, _ = repl.Complete("-v ")
+		Match(t, "web db", values(candidates))
+		candidates, _ = repl.Complete("h")
+		Match(t, "help history", values(candidates))
+		candidates, _ = repl.Complete("--h")
+		Match(t, "--help", values(candidates))
+		_, directive := repl.Complete("--env \"prod")
+		Match(t, completion.Error, directive)
+	})
+}
+
+func TestPrompt(t *testing.T) {
+	opts := []argumentOption.Option{
+		{
+			LongKey:     "port",
+			ShortKey:    "p",
+			ValueType:   "int",
+			Required:    true,
+			Description: "port number.",
+			Validator:   validator.ValidateIntMin,
+			ValidatorParam: validator.ParamInt{
+				Min: 1024,
+			},
+		},
+		{LongKey: "password", ValueType: "string", Required: true, Sensitive: true},
+	}
+	ope := argumentOperand.Operand{
+		Key:       "target",
+		ValueType: "string",
+		Required:  true,
+		Validator: validator.ValidateStringUseable,
+		ValidatorParam: validator.ParamString{
+			Useable: []string{"web", "db"},
+		},
+	}
+
+	t.Run("Missing", func(t *testing.T) {
+		var output bytes.Buffer
+		args := arguments.Args{
+			PromptMissing: true,
+			PromptAlways:  true,
+			PromptInput:   strings.NewReader("\nhttp\n80\n8080\n secret \n2\n"),
+			PromptOutput:  &output,
+		}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperand(ope))
+		NoError(t, args.ParseArgs([]string{"server"}))
+		port, _ := args.GetIntOpt("-p")
+		Match(t, 8080, port)
+		// Sensitive value is not trimmed.
+		password, _ := args.GetSecretOpt("--password")
+		Match(t, " secret ", password)
+		target, _ := args.GetStringOperand("target")
+		Match(t, "db", target)
+		Match(t, true, strings.Contains(output.String(), "--port -p (port number.): Error: Value is required."))
+		Match(t, true, strings.Contains(output.String(), "Error: \"http\" is not int."))
+		Match(t, true, strings.Contains(output.String(), "target\n  1) web\n  2) db\nChoose [1-2]: "))
+	})
+
+	t.Run("Given", func(t *testing.T) {
+		var output bytes.Buffer
+		args := arguments.Args{
+			PromptMissing: true,
+			PromptAlways:  true,
+			PromptInput:   strings.NewReader(""),
+			PromptOutput:  &output,
+		}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperand(ope))
+		NoError(t, args.ParseArgs([]string{"server", "-p", "8080", "--password", "pw", "web"}))
+		Match(t, "", output.String())
+	})
+
+	t.Run("EOF", func(t *testing.T) {
+		var output bytes.Buffer
+		args := arguments.Args{
+			PromptMissing: true,
+			PromptAlways:  true,
+			PromptInput:   strings.NewReader("8080\n"),
+			PromptOutput:  &output,
+		}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperand(ope))
+		WithError(t, args.ParseArgs([]string{"server"}))
+	})
+
+	t.Run("NotTerminal", func(t *testing.T) {
+		var output bytes.Buffer
+		args := arguments.Args{
+			PromptMissing: true,
+			PromptInput:   strings.NewReader("8080\npw\nweb\n"),
+			PromptOutput:  &output,
+		}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.AddOperand(ope))
+		WithError(t, args.ParseArgs([]string{"server"}))
+		Match(t, "", output.String())
+	})
+}
+
+func TestSensitive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "arguments")
+	NoError(t, err)
+	defer os.RemoveAll(dir)
+	tokenPath := filepath.Join(dir, "token")
+	NoError(t, ioutil.WriteFile(tokenPath, []byte("s3cr3t\n"), 0600))
+
+	opt := argumentOption.Option{
+		LongKey:   "token",
+		ValueType: "string",
+		Sensitive: true,
+		FileKey:   "token-file",
+		Validator: validator.ValidateStrlenMin,
+		ValidatorParam: validator.ParamString{
+			Min: 6,
+		},
+	}
+
+	t.Run("Accessor", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOption(opt))
+		NoError(t, args.ParseArgs([]string{"tool", "--token", "s3cr3t"}))
+		token, err := args.GetSecretOpt("--token")
+		NoError(t, err)
+		Match(t, "s3cr3t", token)
+		_, err = args.GetStringOpt("--token")
+		WithError(t, err)
+		_, err = args.GetOpt("--token")
+		WithError(t, err)
+		Match(t, false, strings.Contains(err.Error(), "s3cr3t"))
+	})
+
+	t.Run("Redacted", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOption(opt))
+		err := args.ParseArgs([]string{"tool", "--token", "abc"})
+		WithError(t, err)
+		Match(t, "Invalid value of --token - \"[redacted]\". String length 3 is shorter than min 6.", err.Error())
+
+		args = arguments.Args{}
+		NoError(t, args.AddOption(opt))
+		NoError(t, args.AddOption(argumentOption.Option{
+			LongKey:   "mode",
+			ValueType: "string",
+			Sensitive: true,
+			Validator: validator.ValidateStringUseable,
+			ValidatorParam: validator.ParamString{
+				Useable: []string{"secret-a", "secret-b"},
+			},
+		}))
+		err = args.ParseArgs([]string{"tool", "--token", "s3cr3t", "--mode", "secret-c"})
+		WithError(t, err)
+		Match(t, false, strings.Contains(err.Error(), "secret-c"))
+		Match(t, false, strings.Contains(err.Error(), "Did you mean"))
+	})
+
+	t.Run("File", func(t *testing.T) {
+		var args arguments.Args
+		NoError(t, args.AddOption(opt))
+		NoError(t, args.ParseArgs([]string{"tool", "--token-file", tokenPath}))
+		token, _ := args.GetSecretOpt("--token")
+		Match(t, "s3cr3t", token)
+
+		args = arguments.Args{Stdin: strings.NewReader("from-stdin\r\n")}
+		NoError(t, args.AddOption(opt))
+		NoError(t, args.ParseArgs([]string{"tool", "--token-file", "-"}))
+		token, _ = args.GetSecretOpt("--token")
+		Match(t, "from-stdin", token)
+
+		args = arguments.Args{}
+		NoError(t, args.AddOption(opt))
+		WithError(t, args.ParseArgs([]string{"tool", "--token", "s3cr3t", "--token-file", tokenPath}))
+		args = arguments.Args{}
+		NoError(t, args.AddOption(opt))
+		WithError(t, args.ParseArgs([]string{"tool", "--token-file", filepath.Join(dir, "missing")}))
+	})
+
+	t.Run("Rule", func(t *testing.T) {
+		var args arguments.Args
+		WithError(t, args.AddOption(argumentOption.Option{LongKey: "count", ValueType: "int", Sensitive: true}))
+		WithError(t, args.AddOption(argumentOption.Option{
+			LongKey: "password", ValueType: "string", Sensitive: true, DefaultValue: "admin"}))
+		// The option of FileKey must not collide.
+		NoError(t, args.AddOption(argumentOption.Option{LongKey: "key-file", ValueType: "string"}))
+		WithError(t, args.AddOption(argumentOption.Option{LongKey: "key", ValueType: "string", FileKey: "key-file"}))
+		Match(t, false, args.OptIsSet("--key"))
+		_, err := args.GetOpt("--key")
+		WithError(t, err)
+	})
+}
+
+func TestValueFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "arguments")
+	NoError(t, err)
+	defer os.RemoveAll(dir)
+	policyPath := filepath.Join(dir, "policy.json")
+	NoError(t, ioutil.WriteFile(policyPath, []byte("{\"allow\": true}\n"), 0644))
+	argsPath := filepath.Join(dir, "args.txt")
+	NoError(t, ioutil.WriteFile(argsPath, []byte("--policy @"+policyPath+"\n"), 0644))
+
+	opts := []argumentOption.Option{
+		{LongKey: "policy", ShortKey: "p", ValueType: "string", ValueFromFile: true},
+		{LongKey: "cert", ValueType: "string", ValueFromFile: true},
+		{LongKey: "count", ValueType: "int", ValueFromFile: true},
+		{LongKey: "name", ValueType: "string"},
+	}
+
+	t.Run("File", func(t *testing.T) {
+		for _, argv := range [][]string{
+			{"tool", "--policy", "@" + policyPath},
+			{"tool", "--policy=@" + policyPath},
+			{"tool", "-p", "@" + policyPath},
+			// "@path" given to --policy is not a response file.
+			{"tool", "@" + argsPath},
+		} {
+			args := arguments.Args{ResponseFiles: true}
+			NoError(t, args.AddOptions(opts))
+			NoError(t, args.ParseArgs(argv))
+			policy, _ := args.GetStringOpt("--policy")
+			Match(t, "{\"allow\": true}", policy)
+		}
+	})
+
+	t.Run("Stdin", func(t *testing.T) {
+		args := arguments.Args{Stdin: strings.NewReader("42")}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.ParseArgs([]string{"tool", "--count", "-", "--name", "@plain"}))
+		count, _ := args.GetIntOpt("--count")
+		Match(t, 42, count)
+		name, _ := args.GetStringOpt("--name")
+		Match(t, "@plain", name)
+
+		// Stdin is read at most once.
+		args = arguments.Args{Stdin: strings.NewReader("pem"), ResponseFiles: true}
+		NoError(t, args.AddOptions(opts))
+		err := args.ParseArgs([]string{"tool", "--cert", "-", "--policy", "-"})
+		WithError(t, err)
+		Match(t, "Failed to read the value of --policy from \"-\". Standard input is already read by --cert.", err.Error())
+	})
+
+	t.Run("Int with newline", func(t *testing.T) {
+		countPath := filepath.Join(dir, "count.txt")
+		NoError(t, ioutil.WriteFile(countPath, []byte("42\n"), 0644))
+		args := arguments.Args{ResponseFiles: true}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.ParseArgs([]string{"tool", "--count", "@" + countPath}))
+		count, _ := args.GetIntOpt("--count")
+		Match(t, 42, count)
+
+		// The contents of the file is not written in the error.
+		args = arguments.Args{Stdin: strings.NewReader("4 2\n"), ResponseFiles: true}
+		NoError(t, args.AddOptions(opts))
+		err := args.ParseArgs([]string{"tool", "--count", "-"})
+		WithError(t, err)
+		Match(t, "Failed to parse the value of --count in \"-\". The value is not int.", err.Error())
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		args := arguments.Args{ResponseFiles: true, MaxValueSize: 4}
+		NoError(t, args.AddOptions(opts))
+		WithError(t, args.ParseArgs([]string{"tool", "--policy", "@" + policyPath}))
+		args = arguments.Args{ResponseFiles: true, MaxValueSize: -1}
+		NoError(t, args.AddOptions(opts))
+		NoError(t, args.ParseArgs([]string{"tool", "--policy", "@" + policyPath}))
+		args = arguments.Args{ResponseFiles: true}
+		NoError(t, args.AddOptions(opts))
+		WithError(t, args.ParseArgs([]string{"tool", "--policy", "@" + filepath.Join(dir, "missing")}))
+	})
+}
+
+func TestSources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "arguments")
+	NoError(t, err)
+	defer os.RemoveAll(dir)
+	// Relative paths are used, so that the paths in the golden file don't depend on dir.
+	wd, err := os.Getwd()
+	NoError(t, err)
+	NoError(t, os.Chdir(dir))
+	defer os.Chdir(wd)
+	argsPath := "args.txt"
+	NoError(t, ioutil.WriteFile(argsPath, []byte("# defaults\n--host example.com\n"), 0644))
+	tokenPath := "token"
+	NoError(t, ioutil.WriteFile(tokenPath, []byte("s3cr3t\n"), 0600))
+
+	args := arguments.Args{ResponseFiles: true}
+	NoError(t, args.AddOptions([]argumentOption.Option{
+		{LongKey: "port", ShortKey: "p", ValueType: "int", DefaultValue: 80},
+		{LongKey: "host", ValueType: "string"},
+		{LongKey: "user", ValueType: "string"},
+		{LongKey: "token", ValueType: "string", Sensitive: true, FileKey: "token-file"},
+		{LongKey: "verbose", ShortKey: "v"},
+	}))
+	NoError(t, args.AddOperand(argumentOperand.Operand{Key: "target", ValueType: "string"}))
+	NoError(t, args.ParseArgs([]string{"tool", "-v", "@" + argsPath, "--token-file", tokenPath, "web"}))
+
+	expected := map[string]source.Source{
+		"--port":    {Kind: source.Default},
+		"--host":    {Kind: source.ResponseFile, Path: argsPath, Line: 2},
+		"--user":    {Kind: source.Unset},
+		"--token":   {Kind: source.File, Path: tokenPath},
+		"--verbose": {Kind: source.Argv, Index: 1},
+	}
+	for key, src := range expected {
+		actual, err := args.GetOptSource(key)
+		NoError(t, err)
+		Match(t, src, actual)
+	}
+	src, err := args.GetOperandSource("target")
+	NoError(t, err)
+	Match(t, "argv[5]", src.String())
+	_, err = args.GetOptSource("--unknown")
+	WithError(t, err)
+
+	var output bytes.Buffer
+	NoError(t, args.WriteSources(&output))
+	NoError(t, os.Chdir(wd))
+	Golden(t, "sources", output.String())
+}
+
+func TestConfig(t *testing.T) {
+	args := arguments.Args{}
+	NoError(t, args.AddOptions([]argumentOption.Option{
+		{LongKey: "port", ShortKey: "p", ValueType: "int", DefaultValue: 80},
+		{LongKey: "log-level", ValueType: "string"},
+		{LongKey: "user", ValueType: "string"},
+		{LongKey: "token", ValueType: "string", Sensitive: true},
+		{LongKey: "verbose", ShortKey: "v"},
+	}))
+	NoError(t, args.AddOperand(argumentOperand.Operand{Key: "target", ValueType: "string"}))
+	NoError(t, args.ParseArgs([]string{"tool", "-v", "--log-level", "it's debug", "--token", "s3cr3t", "web"}))
+
+	for _, format := range []string{"json", "yaml", "env"} {
+		var output bytes.Buffer
+		NoError(t, args.WriteConfig(&output, format))
+		Match(t, false, strings.Contains(output.String(), "s3cr3t"))
+		Golden(t, "config."+format, output.String())
+	}
+
+	var output bytes.Buffer
+	NoError(t, args.WriteConfig(&output, "json"))
+	var config arguments.Config
+	NoError(t, json.Unmarshal(output.Bytes(), &config))
+	Match(t, 5, len(config.Options))
+	Match(t, true, config.Options[2].Unset)
+	Match(t, source.Default, config.Options[0].Source.Kind)
+
+	err := args.WriteConfig(&output, "yml")
+	WithError(t, err)
+	Match(t, "Invalid value of format \"yml\". Did you mean \"yaml\"?", err.Error())
+}
@@ -0,0 +1,710 @@
+package arguments
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mozzzzy/arguments/v2/completion"
+	"github.com/mozzzzy/arguments/v2/optionList"
+	"github.com/mozzzzy/arguments/v2/validator"
+)
+
+/*
+ * Types
+ */
+
+// This is an option or operand completed by completion scripts.
+// dynamic is true if the value is completed by calling the program with CompleteCommand.
+type completionOption struct {
+	HelpOption
+	dynamic bool
+}
+
+type completionOperand struct {
+	HelpOperand
+	dynamic bool
+}
+
+/*
+ * Constants and Package Scope Variables
+ */
+
+// CompleteCommand is the hidden first argument with which completion scripts call the program.
+// Parse writes the candidates of the last argument, like "mytool __complete --branch ma",
+// one "value<TAB>description" per line followed by ":<directive>", and returns ErrComplete.
+const CompleteCommand = "__complete"
+
+// ErrComplete is returned by Parse when it is called with CompleteCommand.
+// The candidates have already been written, so the program should exit with status 0.
+var ErrComplete = errors.New("Completion is requested.")
+
+// Shells whose completion script can be written by WriteCompletion
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+var nonIdentifierPattern = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+var zshSpecReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`:`, `\:`,
+)
+
+var zshChoiceReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	` `, `\ `,
+	`(`, `\(`,
+	`)`, `\)`,
+	`:`, `\:`,
+)
+
+var fishQuoteReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`'`, `\'`,
+)
+
+/*
+ * Package Private Functions
+ */
+
+// This function returns the name of shell function for the program, like "_mytool".
+func completionFuncName(name string) string {
+	return "_" + nonIdentifierPattern.ReplaceAllString(name, "_")
+}
+
+// This function quotes str with single quotes for sh.
+func shellQuote(str string) string {
+	return "'" + strings.Replace(str, "'", `'\''`, -1) + "'"
+}
+
+func fishQuote(str string) string {
+	return "'" + fishQuoteReplacer.Replace(str) + "'"
+}
+
+// This function returns the description in one line.
+func completionDescription(description string) string {
+	return strings.Join(strings.Fields(description), " ")
+}
+
+// This function returns the bash commands completing a value.
+// helper is the shell function calling the program with CompleteCommand.
+func bashValueCompletion(helper string, dynamic bool, hint string, choices []string) string {
+	switch {
+	case dynamic:
+		return helper
+	case len(choices) != 0:
+		return `COMPREPLY=($(compgen -W ` + shellQuote(strings.Join(choices, " ")) + ` -- "$cur"))`
+	case hint == "file":
+		return `compopt -o filenames 2>/dev/null; COMPREPLY=($(compgen -f -- "$cur"))`
+	case hint == "dir":
+		return `compopt -o filenames 2>/dev/null; COMPREPLY=($(compgen -d -- "$cur"))`
+	}
+	return "COMPREPLY=()"
+}
+
+// This function returns the zsh _arguments action completing a value.
+func zshValueCompletion(helper string, dynamic bool, hint string, choices []string) string {
+	switch {
+	case dynamic:
+		return helper
+	case len(choices) != 0:
+		var words []string
+		for _, choice := range choices {
+			words = append(words, zshChoiceReplacer.Replace(choice))
+		}
+		return "(" + strings.Join(words, " ") + ")"
+	case hint == "file":
+		return "_files"
+	case hint == "dir":
+		return "_files -/"
+	}
+	return " "
+}
+
+// This function returns the fish complete arguments completing a value.
+func fishValueCompletion(helper string, dynamic bool, hint string, choices []string) string {
+	switch {
+	case dynamic:
+		return "-a " + fishQuote("("+helper+")")
+	case len(choices) != 0:
+		return "-a " + fishQuote(strings.Join(choices, " "))
+	case hint == "file":
+		return "-F"
+	case hint == "dir":
+		return "-a '(__fish_complete_directories (commandline -ct))'"
+	}
+	return ""
+}
+
+// This function returns the candidates of a value.
+// args are the arguments before the value, which are given to the completion function.
+func completeValue(
+	fn completion.Func, hint string, choices []string, args []string, toComplete string,
+) ([]completion.Candidate, completion.Directive) {
+	if fn != nil {
+		return fn(args, toComplete)
+	}
+	if len(choices) != 0 {
+		return completion.Filter(completion.Values(choices), toComplete), completion.NoFileComp
+	}
+	switch hint {
+	case "file":
+		return nil, completion.Default
+	case "dir":
+		return nil, completion.FilterDirs
+	}
+	return nil, completion.NoFileComp
+}
+
+/*
+ * Private Methods
+ */
+
+// This function returns the options completed by completion scripts.
+// Hidden and deprecated options are not completed.
+func (arg Args) completionOptions() []completionOption {
+	var opts []completionOption
+	for _, opt := range arg.optionList.GetOptions() {
+		if !opt.Hidden && !opt.Deprecated {
+			opts = append(opts, completionOption{helpOption(opt), opt.Complete != nil})
+		}
+	}
+	return opts
+}
+
+// This function returns all operands including hidden ones, because completion depends on their positions.
+func (arg Args) completionOperands() []completionOperand {
+	var opes []completionOperand
+	for _, ope := range arg.operandList.GetOperands() {
+		opes = append(opes, completionOperand{helpOperand(ope), ope.Complete != nil})
+	}
+	return opes
+}
+
+// This function returns true if the value of any option or operand is completed by the program.
+func (arg Args) hasDynamicCompletion() bool {
+	for _, opt := range arg.completionOptions() {
+		if opt.dynamic {
+			return true
+		}
+	}
+	for _, ope := range arg.completionOperands() {
+		if ope.dynamic {
+			return true
+		}
+	}
+	return false
+}
+
+// This function returns all keys with prefix of the options which take the next argument as value,
+// including hidden and deprecated ones, so that their values are not counted as operands.
+func (arg Args) valueOptionKeys() []string {
+	var keys []string
+	for _, opt := range arg.optionList.GetOptions() {
+		if !opt.ValueRequired() {
+			continue
+		}
+		for _, longKey := range opt.GetLongKeys() {
+			keys = append(keys, "--"+longKey)
+		}
+		for _, shortKey := range opt.GetShortKeys() {
+			keys = append(keys, "-"+shortKey)
+		}
+	}
+	return keys
+}
+
+// This function returns the candidates of the last word.
+// words are the arguments after CompleteCommand.
+func (args Args) complete(words []string) ([]completion.Candidate, completion.Directive) {
+	toComplete := ""
+	if len(words) != 0 {
+		toComplete = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	// --<long key>=<value>
+	if separator := strings.Index(toComplete, "="); strings.HasPrefix(toComplete, "--") && separator >= 0 {
+		key, err := args.resolveAbbrev(toComplete[:separator])
+		if err != nil {
+			return nil, completion.Error
+		}
+		opt, err := args.optionList.GetOpt(key)
+		if err != nil || !opt.TakesValue() {
+			return nil, completion.Error
+		}
+		return completeValue(opt.Complete, opt.ValueHint,
+			validator.Choices(opt.Validator, opt.ValidatorParam), words, toComplete[separator+1:])
+	}
+
+	// keys of options
+	if strings.HasPrefix(toComplete, "-") {
+		var candidates []completion.Candidate
+		for _, opt := range args.completionOptions() {
+			description := completionDescription(opt.Description)
+			for _, longKey := range opt.LongKeys {
+				candidates = append(candidates, completion.Candidate{Value: "--" + longKey, Description: description})
+			}
+			for _, shortKey := range opt.ShortKeys {
+				candidates = append(candidates, completion.Candidate{Value: "-" + shortKey, Description: description})
+			}
+		}
+		return completion.Filter(candidates, toComplete), completion.NoFileComp
+	}
+
+	// value of the previous option, or operand
+	operandCount := 0
+	for index := 0; index < len(words); index++ {
+		if !optionList.IsOptKey(words[index]) {
+			operandCount++
+			continue
+		}
+		key, err := args.resolveAbbrev(words[index])
+		if err != nil {
+			continue
+		}
+		opt, err := args.optionList.GetOpt(key)
+		if err != nil || !opt.ValueRequired() {
+			continue
+		}
+		index++
+		if index == len(words) {
+			return completeValue(opt.Complete, opt.ValueHint,
+				validator.Choices(opt.Validator, opt.ValidatorParam), words, toComplete)
+		}
+	}
+	operandKeys := args.operandList.GetOpeKeys()
+	if operandCount >= len(operandKeys) {
+		return nil, completion.NoFileComp
+	}
+	ope, err := args.operandList.GetOpe(operandKeys[operandCount])
+	if err != nil {
+		return nil, completion.Error
+	}
+	return completeValue(ope.Complete, ope.ValueHint,
+		validator.Choices(ope.Validator, ope.ValidatorParam), words, toComplete)
+}
+
+// This function writes the candidates of the last word for completion scripts.
+func (args Args) writeCandidates(words []string) {
+	candidates, directive := args.complete(words)
+	for _, candidate := range candidates {
+		fmt.Fprintln(args.output(), candidate)
+	}
+	fmt.Fprintf(args.output(), ":%d\n", directive)
+}
+
+/*
+ * Public Methods
+ */
+
+// WriteCompletion writes the completion script for shell, "bash", "zsh", "fish" or "powershell".
+func (arg Args) WriteCompletion(w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		return arg.WriteBashCompletion(w)
+	case "zsh":
+		return arg.WriteZshCompletion(w)
+	case "fish":
+		return arg.WriteFishCompletion(w)
+	case "powershell":
+		return arg.WritePowerShellCompletion(w)
+	}
+	return &UnknownArgumentError{
+		Arg:         shell,
+		Name:        "shell",
+		Suggestions: arg.suggest(shell, completionShells),
+	}
+}
+
+// WriteBashCompletion writes the completion script for bash.
+// Load it by "source <(mytool --completion bash)" or put it in the bash-completion directory.
+func (arg Args) WriteBashCompletion(w io.Writer) error {
+	name := arg.programName()
+	funcName := completionFuncName(name)
+	helper := "_" + funcName + "_complete"
+
+	buf := bufio.NewWriter(w)
+	line := func(str string) {
+		buf.WriteString(str + "\n")
+	}
+
+	line("# bash completion for " + name)
+	if arg.hasDynamicCompletion() {
+		line("")
+		line("# " + helper + " completes the current word by calling the program with " + CompleteCommand + ".")
+		line(helper + "() {")
+		line(`    local cur="${COMP_WORDS[COMP_CWORD]}" words=() word index joined=0`)
+		line(`    # Join "--key", "=" and "value" split by COMP_WORDBREAKS.`)
+		line("    for ((index = 1; index <= COMP_CWORD; index++)); do")
+		line(`        word="${COMP_WORDS[index]}"`)
+		line(`        if [[ "$word" == "=" && ${#words[@]} -gt 0 && "${words[${#words[@]}-1]}" == --* ]]; then`)
+		line(`            words[${#words[@]}-1]+="="`)
+		line("            joined=1")
+		line("        elif [[ $joined -eq 1 ]]; then")
+		line(`            words[${#words[@]}-1]+="$word"`)
+		line("            joined=0")
+		line("        else")
+		line(`            words+=("$word")`)
+		line("        fi")
+		line("    done")
+		line(`    if [[ "$cur" == "=" ]]; then`)
+		line(`        cur=""`)
+		line("    fi")
+		line("")
+		line("    local output directive line")
+		line(`    output="$("${COMP_WORDS[0]}" ` + CompleteCommand + ` "${words[@]}" 2>/dev/null)" || return`)
+		line(`    directive="${output##*:}"`)
+		line("    COMPREPLY=()")
+		line("    while IFS= read -r line; do")
+		line(`        line="${line%%$'\t'*}"`)
+		line(`        if [[ -n "$line" && "$line" == "$cur"* ]]; then`)
+		line(`            COMPREPLY+=("$line")`)
+		line("        fi")
+		line(`    done <<< "${output%:*}"`)
+		line("    if (( directive & " + strconv.Itoa(int(completion.Error)) + " )); then")
+		line("        COMPREPLY=()")
+		line("        return")
+		line("    fi")
+		line("    if (( directive & " + strconv.Itoa(int(completion.NoSpace)) + " )); then")
+		line("        compopt -o nospace 2>/dev/null")
+		line("    fi")
+		line("    if [[ ${#COMPREPLY[@]} -eq 0 ]]; then")
+		line("        if (( directive & " + strconv.Itoa(int(completion.FilterDirs)) + " )); then")
+		line(`            compopt -o filenames 2>/dev/null; COMPREPLY=($(compgen -d -- "$cur"))`)
+		line("        elif (( (directive & " + strconv.Itoa(int(completion.NoFileComp)) + ") == 0 )); then")
+		line(`            compopt -o filenames 2>/dev/null; COMPREPLY=($(compgen -f -- "$cur"))`)
+		line("        fi")
+		line("    fi")
+		line("}")
+	}
+	line("")
+	line(funcName + "() {")
+	line(`    local cur="${COMP_WORDS[COMP_CWORD]}"`)
+	line(`    local prev="${COMP_WORDS[COMP_CWORD-1]}"`)
+	line("")
+	line(`    # "--key=value" is split into "--key", "=" and "value" by COMP_WORDBREAKS.`)
+	line(`    if [[ "$cur" == "=" ]]; then`)
+	line(`        cur=""`)
+	line(`        prev="${COMP_WORDS[COMP_CWORD-1]}="`)
+	line(`    elif [[ "$prev" == "=" ]]; then`)
+	line(`        prev="${COMP_WORDS[COMP_CWORD-2]}="`)
+	line(`    fi`)
+
+	// values of options
+	var keys []string
+	var valueCases []string
+	for _, opt := range arg.completionOptions() {
+		var patterns []string
+		for _, longKey := range opt.LongKeys {
+			keys = append(keys, "--"+longKey)
+			if opt.ValueType != "" && !opt.ValueOptional {
+				patterns = append(patterns, "--"+longKey)
+			}
+			if opt.ValueType != "" {
+				patterns = append(patterns, "--"+longKey+"=")
+			}
+		}
+		for _, shortKey := range opt.ShortKeys {
+			keys = append(keys, "-"+shortKey)
+			if opt.ValueType != "" && !opt.ValueOptional {
+				patterns = append(patterns, "-"+shortKey)
+			}
+		}
+		if len(patterns) != 0 {
+			valueCases = append(valueCases,
+				"        "+strings.Join(patterns, "|")+")",
+				"            "+bashValueCompletion(helper, opt.dynamic, opt.ValueHint, opt.Choices),
+				"            return",
+				"            ;;")
+		}
+	}
+	if len(valueCases) != 0 {
+		line("")
+		line(`    case "$prev" in`)
+		for _, valueCase := range valueCases {
+			line(valueCase)
+		}
+		line("    esac")
+	}
+
+	// keys of options
+	line("")
+	line(`    if [[ "$cur" == -* ]]; then`)
+	line(`        COMPREPLY=($(compgen -W ` + shellQuote(strings.Join(keys, " ")) + ` -- "$cur"))`)
+	line("        return")
+	line("    fi")
+
+	// operands
+	var operandCases []string
+	for index, ope := range arg.completionOperands() {
+		if !ope.dynamic && ope.ValueHint == "" && len(ope.Choices) == 0 {
+			continue
+		}
+		operandCases = append(operandCases,
+			"        "+strconv.Itoa(index)+")",
+			"            "+bashValueCompletion(helper, ope.dynamic, ope.ValueHint, ope.Choices),
+			"            ;;")
+	}
+	if len(operandCases) != 0 {
+		line("")
+		line("    # Count operands before the current word.")
+		line("    local index count=0")
+		line("    for ((index = 1; index < COMP_CWORD; index++)); do")
+		line(`        case "${COMP_WORDS[index]}" in`)
+		if valueKeys := arg.valueOptionKeys(); len(valueKeys) != 0 {
+			line("            " + strings.Join(valueKeys, "|") + ")")
+			line(`                if [[ "${COMP_WORDS[index+1]}" == "=" ]]; then`)
+			line("                    index=$((index + 1))")
+			line("                fi")
+			line("                index=$((index + 1))")
+			line("                ;;")
+		}
+		line("            =)")
+		line("                index=$((index + 1))")
+		line("                ;;")
+		line("            -*)")
+		line("                ;;")
+		line("            *)")
+		line("                count=$((count + 1))")
+		line("                ;;")
+		line("        esac")
+		line("    done")
+		line(`    case "$count" in`)
+		for _, operandCase := range operandCases {
+			line(operandCase)
+		}
+		line("    esac")
+	}
+	line("}")
+	line("")
+	line("complete -F " + funcName + " " + name)
+
+	return buf.Flush()
+}
+
+// WriteZshCompletion writes the completion script for zsh.
+// Put it as "_mytool" in a directory of $fpath.
+func (arg Args) WriteZshCompletion(w io.Writer) error {
+	name := arg.programName()
+	funcName := completionFuncName(name)
+	helper := "_" + funcName + "_complete"
+
+	var specs []string
+	for _, opt := range arg.completionOptions() {
+		var keys []string
+		for _, longKey := range opt.LongKeys {
+			keys = append(keys, "--"+longKey)
+		}
+		for _, shortKey := range opt.ShortKeys {
+			keys = append(keys, "-"+shortKey)
+		}
+		exclusion := "(" + strings.Join(keys, " ") + ")"
+		description := "[" + zshSpecReplacer.Replace(completionDescription(opt.Description)) + "]"
+		value := ""
+		if opt.ValueType != "" {
+			value = ":" + zshSpecReplacer.Replace(opt.ValueName) + ":" +
+				zshValueCompletion(helper, opt.dynamic, opt.ValueHint, opt.Choices)
+		}
+		for _, key := range keys {
+			switch {
+			case opt.ValueType == "":
+				specs = append(specs, exclusion+key+description)
+			case opt.ValueOptional && strings.HasPrefix(key, "--"):
+				specs = append(specs, exclusion+key+"=-"+description+":"+value)
+			case opt.ValueOptional:
+				specs = append(specs, exclusion+key+description)
+			case strings.HasPrefix(key, "--"):
+				specs = append(specs, exclusion+key+"="+description+value)
+			default:
+				// Short options take the value in the next argument, and are not stacked.
+				specs = append(specs, exclusion+key+description+value)
+			}
+		}
+	}
+	for index, ope := range arg.completionOperands() {
+		position := strconv.Itoa(index+1) + ":"
+		if !ope.Required {
+			position += ":"
+		}
+		specs = append(specs, position+zshSpecReplacer.Replace(ope.Key)+":"+
+			zshValueCompletion(helper, ope.dynamic, ope.ValueHint, ope.Choices))
+	}
+
+	buf := bufio.NewWriter(w)
+	line := func(str string) {
+		buf.WriteString(str + "\n")
+	}
+
+	line("#compdef " + name)
+	line("")
+	line("# zsh completion for " + name)
+	if arg.hasDynamicCompletion() {
+		line("")
+		line("# " + helper + " completes the current word by calling the program with " + CompleteCommand + ".")
+		line(helper + "() {")
+		line("  local output directive line value")
+		line("  local -a lines candidates")
+		line(`  output="$("${words[1]}" ` + CompleteCommand + ` "${(@)words[2,CURRENT-1]}" "$IPREFIX$PREFIX" 2>/dev/null)" || return 1`)
+		line(`  lines=("${(@f)output}")`)
+		line(`  directive="${lines[-1]#:}"`)
+		line(`  for line in "${(@)lines[1,-2]}"; do`)
+		line(`    value="${line%%$'\t'*}"`)
+		line(`    value="${value//:/\\:}"`)
+		line(`    if [[ "$line" == *$'\t'* ]]; then`)
+		line(`      candidates+=("$value:${line#*$'\t'}")`)
+		line("    else")
+		line(`      candidates+=("$value")`)
+		line("    fi")
+		line("  done")
+		line("  if (( directive & " + strconv.Itoa(int(completion.Error)) + " )); then")
+		line("    return 1")
+		line("  fi")
+		line("  if (( ${#candidates} )); then")
+		line("    if (( directive & " + strconv.Itoa(int(completion.NoSpace)) + " )); then")
+		line("      _describe -t values value candidates -S ''")
+		line("    else")
+		line("      _describe -t values value candidates")
+		line("    fi")
+		line("  elif (( directive & " + strconv.Itoa(int(completion.FilterDirs)) + " )); then")
+		line("    _files -/")
+		line("  elif (( (directive & " + strconv.Itoa(int(completion.NoFileComp)) + ") == 0 )); then")
+		line("    _files")
+		line("  fi")
+		line("}")
+	}
+	line("")
+	line(funcName + "() {")
+	if len(specs) == 0 {
+		line("  _arguments")
+	} else {
+		line(`  _arguments \`)
+	}
+	for index, spec := range specs {
+		if index == len(specs)-1 {
+			line("    " + shellQuote(spec))
+		} else {
+			line("    " + shellQuote(spec) + ` \`)
+		}
+	}
+	line("}")
+	line("")
+	line(`if [ "$funcstack[1]" = "` + funcName + `" ]; then`)
+	line(`  ` + funcName + ` "$@"`)
+	line("else")
+	line("  compdef " + funcName + " " + name)
+	line("fi")
+
+	return buf.Flush()
+}
+
+// WriteFishCompletion writes the completion script for fish.
+// Put it as "mytool.fish" in ~/.config/fish/completions.
+func (arg Args) WriteFishCompletion(w io.Writer) error {
+	name := arg.programName()
+	helper := "_" + completionFuncName(name) + "_complete"
+	countFunc := "_" + completionFuncName(name) + "_operand_count"
+
+	buf := bufio.NewWriter(w)
+	line := func(str string) {
+		buf.WriteString(str + "\n")
+	}
+
+	line("# fish completion for " + name)
+
+	if arg.hasDynamicCompletion() {
+		line("")
+		line("# " + helper + " completes the current token by calling the program with " + CompleteCommand + ".")
+		line("function " + helper)
+		line("    set -l tokens (commandline -opc)")
+		line("    set -l program $tokens[1]")
+		line("    set -e tokens[1]")
+		line("    set -l output ($program " + CompleteCommand + " $tokens (commandline -ct) 2>/dev/null)")
+		line("    or return")
+		line("    set -l directive (string replace ':' '' -- $output[-1])")
+		line("    set -e output[-1]")
+		line(`    if test (math "bitand($directive, ` + strconv.Itoa(int(completion.Error)) + `)") -ne 0`)
+		line("        return")
+		line("    end")
+		line("    if test (count $output) -ne 0")
+		line("        printf '%s\\n' $output")
+		line(`    else if test (math "bitand($directive, ` + strconv.Itoa(int(completion.FilterDirs)) + `)") -ne 0`)
+		line("        __fish_complete_directories (commandline -ct)")
+		line(`    else if test (math "bitand($directive, ` + strconv.Itoa(int(completion.NoFileComp)) + `)") -eq 0`)
+		line("        __fish_complete_path (commandline -ct)")
+		line("    end")
+		line("end")
+	}
+
+	var operandLines []string
+	for index, ope := range arg.completionOperands() {
+		value := fishValueCompletion(helper, ope.dynamic, ope.ValueHint, ope.Choices)
+		if value == "" {
+			continue
+		}
+		operandLines = append(operandLines,
+			"complete -c "+name+" -n "+fishQuote("test ("+countFunc+") -eq "+strconv.Itoa(index))+
+				" "+value+" -d "+fishQuote(completionDescription(ope.Description)))
+	}
+	if len(operandLines) != 0 {
+		line("")
+		line("function " + countFunc)
+		line("    set -l tokens (commandline -opc)")
+		line("    set -e tokens[1]")
+		line("    set -l count 0")
+		line("    set -l skip 0")
+		line("    for token in $tokens")
+		line("        if test $skip -eq 1")
+		line("            set skip 0")
+		line("            continue")
+		line("        end")
+		line("        switch $token")
+		if valueKeys := arg.valueOptionKeys(); len(valueKeys) != 0 {
+			line("            case " + strings.Join(valueKeys, " "))
+			line("                set skip 1")
+		}
+		line("            case '-*'")
+		line("            case '*'")
+		line("                set count (math $count + 1)")
+		line("        end")
+		line("    end")
+		line("    echo $count")
+		line("end")
+	}
+
+	line("")
+	// Files are completed only for the values whose ValueHint is "file".
+	line("complete -c " + name + " -f")
+	for _, opt := range arg.completionOptions() {
+		str := "complete -c " + name
+		for _, longKey := range opt.LongKeys {
+			str += " -l " + longKey
+		}
+		for _, shortKey := range opt.ShortKeys {
+			str += " -s " + shortKey
+		}
+		// fish can't complete optional values, so the option is completed as a flag.
+		if opt.ValueType != "" && !opt.ValueOptional {
+			str += " -r"
+			if value := fishValueCompletion(helper, opt.dynamic, opt.ValueHint, opt.Choices); value != "" {
+				str += " " + value
+			}
+		}
+		str += " -d " + fishQuote(completionDescription(opt.Description))
+		line(str)
+	}
+	for _, operandLine := range operandLines {
+		line(operandLine)
+	}
+
+	return buf.Flush()
+}
@@ -0,0 +1,80 @@
+package completion
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"strings"
+)
+
+/*
+ * Types
+ */
+
+// Candidate is a value completed by shells. Description is shown by shells which support it.
+type Candidate struct {
+	Value       string
+	Description string
+}
+
+// Directive tells shells how to handle the candidates. Directives are combined by "|".
+type Directive int
+
+// Func returns the candidates of the value being completed.
+// args are the arguments before the value without the executed file name,
+// and toComplete is the partially typed value.
+type Func func(args []string, toComplete string) ([]Candidate, Directive)
+
+/*
+ * Constants and Package Scope Variables
+ */
+
+const (
+	// File names are completed if there is no candidate.
+	Default Directive = 0
+	// Completion failed. Nothing is completed.
+	Error Directive = 1 << (iota - 1)
+	// No space is added after the completed value.
+	NoSpace
+	// File names are not completed even if there is no candidate.
+	NoFileComp
+	// Directory names are completed if there is no candidate.
+	FilterDirs
+)
+
+/*
+ * Public Functions
+ */
+
+// Filter returns the candidates which start with prefix.
+func Filter(candidates []Candidate, prefix string) []Candidate {
+	filtered := []Candidate{}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate.Value, prefix) {
+			filtered = append(filtered, candidate)
+		}
+	}
+	return filtered
+}
+
+// Values returns the candidates of values without description.
+func Values(values []string) []Candidate {
+	candidates := []Candidate{}
+	for _, value := range values {
+		candidates = append(candidates, Candidate{Value: value})
+	}
+	return candidates
+}
+
+/*
+ * Public Methods
+ */
+
+// String returns the line written by the completion entrypoint, like "value\tdescription".
+func (candidate Candidate) String() string {
+	if candidate.Description == "" {
+		return candidate.Value
+	}
+	return candidate.Value + "\t" + candidate.Description
+}
@@ -0,0 +1,56 @@
+package completion_test
+
+import (
+	"testing"
+
+	"github.com/mozzzzy/arguments/v2/completion"
+)
+
+/*
+ * Functions
+ */
+
+func Match(t *testing.T, expected interface{}, actual interface{}) {
+	if expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+/*
+ * Tests
+ */
+
+func TestDirective(t *testing.T) {
+	// Directives are bits written by the entrypoint and read by the scripts.
+	Match(t, completion.Directive(0), completion.Default)
+	Match(t, completion.Directive(1), completion.Error)
+	Match(t, completion.Directive(2), completion.NoSpace)
+	Match(t, completion.Directive(4), completion.NoFileComp)
+	Match(t, completion.Directive(8), completion.FilterDirs)
+}
+
+func TestFilter(t *testing.T) {
+	candidates := []completion.Candidate{
+		{Value: "main", Description: "default branch"},
+		{Value: "master"},
+		{Value: "develop"},
+	}
+	filtered := completion.Filter(candidates, "ma")
+	Match(t, 2, len(filtered))
+	Match(t, "main", filtered[0].Value)
+	Match(t, "master", filtered[1].Value)
+	Match(t, 3, len(completion.Filter(candidates, "")))
+	Match(t, 0, len(completion.Filter(candidates, "x")))
+}
+
+func TestValues(t *testing.T) {
+	candidates := completion.Values([]string{"json", "text"})
+	Match(t, 2, len(candidates))
+	Match(t, completion.Candidate{Value: "text"}, candidates[1])
+	Match(t, 0, len(completion.Values(nil)))
+}
+
+func TestCandidateString(t *testing.T) {
+	Match(t, "main\tdefault branch", completion.Candidate{Value: "main", Description: "default branch"}.String())
+	Match(t, "master", completion.Candidate{Value: "master"}.String())
+}
@@ -0,0 +1,271 @@
+package arguments
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"bufio"
+	"html/template"
+	"io"
+	"strings"
+)
+
+/*
+ * Types
+ */
+
+/*
+ * Constants and Package Scope Variables
+ */
+
+var markdownCellReplacer = strings.NewReplacer(
+	`|`, `\|`,
+	"\n", " ",
+)
+
+const htmlDocTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Name}}</title>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+{{- range paragraphs .Description}}
+<p>{{.}}</p>
+{{- end}}
+<h2>Synopsis</h2>
+<pre><code>{{.Synopsis}}</code></pre>
+{{- if .Options}}
+<h2>Options</h2>
+{{- range .Groups}}
+{{- if ne .Name "Options"}}
+<h3>{{.Name}}</h3>
+{{- end}}
+{{- if .Description}}
+<p>{{.Description}}</p>
+{{- end}}
+<table>
+<thead><tr><th>Option</th><th>Type</th><th>Default</th><th>Required</th><th>Constraint</th><th>Description</th></tr></thead>
+<tbody>
+{{- range .Options}}
+<tr><td>{{range $index, $key := optionKeys .}}{{if $index}}, {{end}}<code>{{$key}}</code>{{end}}</td><td>{{.ValueType}}</td><td>{{.Default}}</td><td>{{if .Required}}yes{{end}}</td><td>{{.Constraint}}</td><td>{{description .Description .Deprecated .ReplacedBy}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{- end}}
+{{- if .Operands}}
+<h2>Operands</h2>
+<table>
+<thead><tr><th>Operand</th><th>Type</th><th>Default</th><th>Required</th><th>Constraint</th><th>Description</th></tr></thead>
+<tbody>
+{{- range .Operands}}
+<tr><td><code>{{.Key}}</code></td><td>{{.ValueType}}</td><td>{{.Default}}</td><td>{{if .Required}}yes{{end}}</td><td>{{.Constraint}}</td><td>{{description .Description .Deprecated .ReplacedBy}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{- if .Environment}}
+<h2>Environment</h2>
+<table>
+<thead><tr><th>Variable</th><th>Description</th></tr></thead>
+<tbody>
+{{- range .Environment}}
+<tr><td><code>{{.Name}}</code></td><td>{{.Description}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{- if .Examples}}
+<h2>Examples</h2>
+{{- range .Examples}}
+{{- if .Description}}
+<p>{{.Description}}</p>
+{{- end}}
+<pre><code>$ {{.Command}}</code></pre>
+{{- end}}
+{{- end}}
+{{- range paragraphs .Epilog}}
+<p>{{.}}</p>
+{{- end}}
+</body>
+</html>
+`
+
+/*
+ * Package Private Functions
+ */
+
+func markdownCell(text string) string {
+	return markdownCellReplacer.Replace(strings.TrimSpace(text))
+}
+
+// This function returns the keys of option with prefix, like "--output", "--out" and "-o".
+// The optional value is attached to the last long key like "--color[=WHEN]".
+func optionKeys(opt HelpOption) []string {
+	var keys []string
+	for _, longKey := range opt.LongKeys {
+		keys = append(keys, "--"+longKey)
+	}
+	if opt.ValueType != "" && opt.ValueOptional {
+		keys[len(keys)-1] += "[=" + opt.ValueName + "]"
+	}
+	for _, shortKey := range opt.ShortKeys {
+		keys = append(keys, "-"+shortKey)
+	}
+	return keys
+}
+
+// This function returns the description with deprecation notice.
+func docDescription(description string, deprecated bool, replacedBy string) string {
+	if !deprecated {
+		return description
+	}
+	notice := "Deprecated."
+	if replacedBy != "" {
+		notice = "Deprecated, use " + replacedBy + "."
+	}
+	return strings.TrimSpace(notice + " " + description)
+}
+
+// This function splits text into paragraphs by empty lines.
+func paragraphs(text string) []string {
+	var strs []string
+	for _, paragraph := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		if strings.TrimSpace(paragraph) != "" {
+			strs = append(strs, strings.TrimSpace(paragraph))
+		}
+	}
+	return strs
+}
+
+/*
+ * Public Methods
+ */
+
+// WriteMarkdown writes the reference of options and operands in Markdown.
+func (arg Args) WriteMarkdown(w io.Writer) error {
+	data := arg.HelpData()
+
+	buf := bufio.NewWriter(w)
+	line := func(str string) {
+		buf.WriteString(str + "\n")
+	}
+
+	line("# " + data.Name)
+	for _, paragraph := range paragraphs(data.Description) {
+		line("")
+		line(paragraph)
+	}
+
+	line("")
+	line("## Synopsis")
+	line("")
+	line("```")
+	line(data.Synopsis)
+	line("```")
+
+	if len(data.Options) != 0 {
+		line("")
+		line("## Options")
+		for _, group := range data.Groups {
+			if group.Name != "Options" {
+				line("")
+				line("### " + group.Name)
+			}
+			if group.Description != "" {
+				line("")
+				line(group.Description)
+			}
+			line("")
+			line("| Option | Type | Default | Required | Constraint | Description |")
+			line("| --- | --- | --- | --- | --- | --- |")
+			for _, opt := range group.Options {
+				keys := optionKeys(opt)
+				for index := range keys {
+					keys[index] = "`" + markdownCell(keys[index]) + "`"
+				}
+				required := ""
+				if opt.Required {
+					required = "yes"
+				}
+				line("| " + strings.Join(keys, ", ") +
+					" | " + markdownCell(opt.ValueType) +
+					" | " + markdownCell(opt.Default) +
+					" | " + required +
+					" | " + markdownCell(opt.Constraint) +
+					" | " + markdownCell(docDescription(opt.Description, opt.Deprecated, opt.ReplacedBy)) +
+					" |")
+			}
+		}
+	}
+
+	if len(data.Operands) != 0 {
+		line("")
+		line("## Operands")
+		line("")
+		line("| Operand | Type | Default | Required | Constraint | Description |")
+		line("| --- | --- | --- | --- | --- | --- |")
+		for _, ope := range data.Operands {
+			required := ""
+			if ope.Required {
+				required = "yes"
+			}
+			line("| `" + markdownCell(ope.Key) + "`" +
+				" | " + markdownCell(ope.ValueType) +
+				" | " + markdownCell(ope.Default) +
+				" | " + required +
+				" | " + markdownCell(ope.Constraint) +
+				" | " + markdownCell(docDescription(ope.Description, ope.Deprecated, ope.ReplacedBy)) +
+				" |")
+		}
+	}
+
+	if len(data.Environment) != 0 {
+		line("")
+		line("## Environment")
+		line("")
+		line("| Variable | Description |")
+		line("| --- | --- |")
+		for _, env := range data.Environment {
+			line("| `" + markdownCell(env.Name) + "` | " + markdownCell(env.Description) + " |")
+		}
+	}
+
+	if len(data.Examples) != 0 {
+		line("")
+		line("## Examples")
+		for _, example := range data.Examples {
+			if example.Description != "" {
+				line("")
+				line(example.Description)
+			}
+			line("")
+			line("```sh")
+			line("$ " + example.Command)
+			line("```")
+		}
+	}
+
+	for _, paragraph := range paragraphs(data.Epilog) {
+		line("")
+		line(paragraph)
+	}
+
+	return buf.Flush()
+}
+
+// WriteHTML writes the reference of options and operands as a standalone HTML document.
+func (arg Args) WriteHTML(w io.Writer) error {
+	tmpl, err := template.New("html").Funcs(template.FuncMap{
+		"optionKeys":  optionKeys,
+		"description": docDescription,
+		"paragraphs":  paragraphs,
+	}).Parse(htmlDocTemplate)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, arg.HelpData())
+}
@@ -0,0 +1,206 @@
+package arguments
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mozzzzy/arguments/v2/argumentOption"
+	"github.com/mozzzzy/arguments/v2/source"
+)
+
+/*
+ * Types
+ */
+
+// ConfigValue is the effective value of an option or operand written by WriteConfig.
+// Value is nil and Unset is true if neither the value nor the default value is set.
+// Value is argumentOption.Redacted and Redacted is true if the option is sensitive.
+// Options without ValueType have bool values which tell whether they are specified.
+type ConfigValue struct {
+	// Name like "--port" or the key of operand
+	Name     string        `json:"name"`
+	Type     string        `json:"type"`
+	Value    interface{}   `json:"value"`
+	Unset    bool          `json:"unset,omitempty"`
+	Redacted bool          `json:"redacted,omitempty"`
+	Source   source.Source `json:"source"`
+}
+
+// Config is the effective configuration written by WriteConfig.
+type Config struct {
+	Options  []ConfigValue `json:"options"`
+	Operands []ConfigValue `json:"operands"`
+}
+
+/*
+ * Constants and Package Scope Variables
+ */
+
+var configFormats = []string{"json", "yaml", "env"}
+
+var envNameInvalidChars = regexp.MustCompile(`[^A-Z0-9_]`)
+
+/*
+ * Package Private Functions
+ */
+
+func newConfigValue(name string, valueType string, value interface{}, err error, src source.Source) ConfigValue {
+	configValue := ConfigValue{Name: name, Type: valueType, Value: value, Source: src}
+	if err != nil {
+		configValue.Value = nil
+		configValue.Unset = true
+	}
+	return configValue
+}
+
+// This function writes configValues as a YAML list of key.
+func writeYAMLValues(line func(str string), key string, configValues []ConfigValue) {
+	if len(configValues) == 0 {
+		line(key + ": []")
+		return
+	}
+	line(key + ":")
+	for _, configValue := range configValues {
+		value := configValue.displayValue()
+		comment := ""
+		switch {
+		case configValue.Unset:
+			value = "null"
+			comment = "  # unset"
+		case configValue.Redacted:
+			value = fmt.Sprintf("%q", value)
+			comment = "  # redacted"
+		}
+		line(fmt.Sprintf("  - name: %q", configValue.Name))
+		line("    type: " + configValue.Type)
+		line("    value: " + value + comment)
+		line(fmt.Sprintf("    source: %q", configValue.Source.String()))
+	}
+}
+
+/*
+ * Private Methods
+ */
+
+// This function returns the value shown in text. Strings are quoted.
+func (configValue ConfigValue) displayValue() string {
+	switch {
+	case configValue.Unset:
+		return "<unset>"
+	case configValue.Redacted:
+		return argumentOption.Redacted
+	case configValue.Type == "string":
+		return fmt.Sprintf("%q", configValue.Value)
+	}
+	return fmt.Sprint(configValue.Value)
+}
+
+// This function returns the name of environment variable, like "LOG_LEVEL" for "--log-level".
+func (configValue ConfigValue) envName() string {
+	name := strings.ToUpper(strings.TrimLeft(configValue.Name, "-"))
+	return envNameInvalidChars.ReplaceAllString(name, "_")
+}
+
+/*
+ * Public Methods
+ */
+
+// GetConfig returns the effective values of options and operands in the order they are added.
+// Values of sensitive options are redacted.
+func (args Args) GetConfig() Config {
+	config := Config{Options: []ConfigValue{}, Operands: []ConfigValue{}}
+	for _, opt := range args.optionList.GetOptions() {
+		name := strings.Fields(opt.Name())[0]
+		if !opt.TakesValue() {
+			config.Options = append(config.Options,
+				newConfigValue(name, "bool", opt.Set, nil, opt.GetSource()))
+			continue
+		}
+		value, err := opt.GetValue()
+		configValue := newConfigValue(name, opt.ValueType, value, err, opt.GetSource())
+		if opt.Sensitive && !configValue.Unset {
+			configValue.Value = argumentOption.Redacted
+			configValue.Redacted = true
+		}
+		config.Options = append(config.Options, configValue)
+	}
+	for _, ope := range args.operandList.GetOperands() {
+		value, err := ope.GetValue()
+		config.Operands = append(config.Operands,
+			newConfigValue(ope.Key, ope.ValueType, value, err, ope.GetSource()))
+	}
+	return config
+}
+
+// WriteSources writes the effective values of options and operands and where they come from,
+// like "--port  8080  argv[2]", so that operators can explain the configuration.
+// Values of sensitive options are redacted.
+func (args Args) WriteSources(w io.Writer) error {
+	config := args.GetConfig()
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, configValue := range append(config.Options, config.Operands...) {
+		fmt.Fprintf(tw, "%v\t%v\t%v\n", configValue.Name, configValue.displayValue(), configValue.Source)
+	}
+	return tw.Flush()
+}
+
+// WriteConfig writes the effective values of options and operands with their types and sources,
+// for diagnostics like --print-config. format is "json", "yaml" or "env".
+// "env" writes KEY=value lines which shells can read, like "LOG_LEVEL='debug'" for --log-level.
+// Values of sensitive options are redacted, and unset values are marked.
+func (args Args) WriteConfig(w io.Writer, format string) error {
+	config := args.GetConfig()
+
+	buf := bufio.NewWriter(w)
+	line := func(str string) {
+		buf.WriteString(str + "\n")
+	}
+
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return err
+		}
+		line(string(data))
+	case "yaml":
+		line("# configuration of " + args.programName())
+		writeYAMLValues(line, "options", config.Options)
+		writeYAMLValues(line, "operands", config.Operands)
+	case "env":
+		line("# configuration of " + args.programName())
+		for _, configValue := range append(config.Options, config.Operands...) {
+			comment := fmt.Sprintf("# %v (%v)", configValue.Name, configValue.Type)
+			if configValue.Source.Kind != source.Unset {
+				comment += " from " + configValue.Source.String()
+			}
+			line(comment)
+			switch {
+			case configValue.Unset:
+				line("# " + configValue.envName() + " is unset.")
+			case configValue.Redacted:
+				line("# " + configValue.envName() + " is redacted.")
+			case configValue.Type == "string":
+				line(configValue.envName() + "=" + shellQuote(configValue.Value.(string)))
+			default:
+				line(fmt.Sprintf("%v=%v", configValue.envName(), configValue.Value))
+			}
+		}
+	default:
+		return &UnknownArgumentError{
+			Arg:         format,
+			Name:        "format",
+			Suggestions: args.suggest(format, configFormats),
+		}
+	}
+	return buf.Flush()
+}
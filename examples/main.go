@@ -5,6 +5,7 @@ package main
  */
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/mozzzzy/arguments/v2"
@@ -26,7 +27,7 @@ import (
  */
 
 func main() {
-	var args arguments.Args
+	args := arguments.Args{AutoHelp: true}
 
 	opt1 := argumentOption.Option{
 		LongKey:        "string",
@@ -88,6 +89,10 @@ func main() {
 	}
 
 	if err := args.Parse(); err != nil {
+		// --help -h is specified. The usage is already printed.
+		if errors.Is(err, arguments.ErrHelp) {
+			return
+		}
 		fmt.Println(err.Error())
 		fmt.Println(args)
 		return
@@ -0,0 +1,348 @@
+package arguments
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/mozzzzy/arguments/v2/argumentOperand"
+	"github.com/mozzzzy/arguments/v2/argumentOption"
+	"github.com/mozzzzy/arguments/v2/helpFormatter"
+	"github.com/mozzzzy/arguments/v2/validator"
+)
+
+/*
+ * Types
+ */
+
+// Example is a command line shown in the examples section of usage.
+type Example struct {
+	Description string
+	Command     string
+}
+
+// EnvironmentVariable is an environment variable which the program reads.
+// It is shown in the environment section of usage and man page.
+type EnvironmentVariable struct {
+	Name        string
+	Description string
+}
+
+// HelpData is the data given to HelpTemplate.
+// Hidden options and operands are not included.
+type HelpData struct {
+	// Base name of the executed file
+	Name        string
+	Synopsis    string
+	Description string
+	// All options in the order they are added
+	Options []HelpOption
+	// Options grouped into sections. Options without Group are in the first section "Options".
+	Groups      []HelpGroup
+	Operands    []HelpOperand
+	Environment []EnvironmentVariable
+	Examples    []Example
+	Epilog      string
+	// Width of usage. 0 or a negative value means no wrapping.
+	Width int
+}
+
+// HelpGroup is a section of options in HelpData.
+type HelpGroup struct {
+	Name        string
+	Description string
+	Options     []HelpOption
+}
+
+// HelpOption is an option in HelpData.
+type HelpOption struct {
+	// Keys, aliases and the value, like "--output|--out -o string"
+	Keys      string
+	LongKeys  []string
+	ShortKeys []string
+	// ValueType is empty if the option takes no value.
+	ValueType     string
+	ValueName     string
+	ValueOptional bool
+	// Kind of the value, "file" or "dir"
+	ValueHint   string
+	Description string
+	Group       string
+	// Constraint checked by the validator, like "min: 10, max: 100"
+	Constraint string
+	// Useable values checked by the validator
+	Choices    []string
+	Required   bool
+	Deprecated bool
+	ReplacedBy string
+	// Default value formatted like "\"str\"" or "10". Empty if the option has no default value.
+	Default string
+	// Annotations like "(required)" and "(default: 10)"
+	Annotations []string
+}
+
+// HelpOperand is an operand in HelpData.
+type HelpOperand struct {
+	Key       string
+	ValueType string
+	// Kind of the value, "file" or "dir"
+	ValueHint   string
+	Description string
+	// Constraint checked by the validator, like "min: 10, max: 100"
+	Constraint string
+	// Useable values checked by the validator
+	Choices    []string
+	Required   bool
+	Deprecated bool
+	ReplacedBy string
+	// Default value formatted like "\"str\"" or "10". Empty if the operand has no default value.
+	Default string
+	// Annotations like "(required)" and "(default: 10)"
+	Annotations []string
+}
+
+/*
+ * Constants and Package Scope Variables
+ */
+
+// DefaultHelpTemplate is the template used if HelpTemplate is empty.
+// Besides the functions of text/template, the following functions are available.
+//
+//	optionTable .Options   : aligned and wrapped lines of options
+//	operandTable .Operands : aligned and wrapped lines of operands
+//	envTable .Environment  : aligned and wrapped lines of environment variables
+//	wrap .Width indent text: text wrapped with indent
+const DefaultHelpTemplate = `
+Usage:
+  {{.Synopsis}}
+{{- if .Description}}
+
+{{wrap .Width "  " .Description}}
+{{- end}}
+
+{{range .Groups}}  {{.Name}}
+{{- if .Description}}
+{{wrap $.Width "    " .Description}}
+{{- end}}
+{{optionTable .Options}}
+{{else}}
+{{end}}
+{{- if .Operands}}  Operands
+{{operandTable .Operands}}{{end}}
+{{- if .Environment}}{{if .Operands}}{{"\n"}}{{end}}  Environment
+{{envTable .Environment}}{{end}}
+{{- if .Examples}}{{if or .Operands .Environment}}{{"\n"}}{{end}}  Examples
+{{- range .Examples}}
+{{- if .Description}}
+{{wrap $.Width "    # " .Description}}
+{{- end}}
+    $ {{.Command}}
+{{- end}}
+{{end}}
+{{- if .Epilog}}
+{{wrap .Width "" .Epilog}}
+{{end}}`
+
+/*
+ * Package Private Functions
+ */
+
+// This function returns a formatted default value, or an empty string if there is no default value.
+func formatDefault(defaultValue interface{}) string {
+	switch value := defaultValue.(type) {
+	case string:
+		return "\"" + value + "\""
+	case int:
+		return fmt.Sprint(value)
+	}
+	return ""
+}
+
+// This function indents each line of text, and wraps them if width is positive.
+func wrap(width int, indent string, text string) string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		if strings.TrimSpace(paragraph) == "" {
+			lines = append(lines, "")
+			continue
+		}
+		if width <= 0 {
+			lines = append(lines, indent+paragraph)
+			continue
+		}
+		for _, line := range helpFormatter.Wrap(strings.Fields(paragraph), width-helpFormatter.StringWidth(indent)) {
+			lines = append(lines, indent+line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func helpFuncs(width int) template.FuncMap {
+	return template.FuncMap{
+		"wrap": wrap,
+		"optionTable": func(opts []HelpOption) string {
+			var entries []helpFormatter.Entry
+			for _, opt := range opts {
+				entries = append(entries, helpFormatter.Entry{
+					Key:         opt.Keys,
+					Description: opt.Description,
+					Annotations: opt.Annotations,
+				})
+			}
+			return helpFormatter.Format(entries, "    ", width)
+		},
+		"envTable": func(envs []EnvironmentVariable) string {
+			var entries []helpFormatter.Entry
+			for _, env := range envs {
+				entries = append(entries, helpFormatter.Entry{
+					Key:         env.Name,
+					Description: env.Description,
+				})
+			}
+			return helpFormatter.Format(entries, "    ", width)
+		},
+		"operandTable": func(opes []HelpOperand) string {
+			var entries []helpFormatter.Entry
+			for _, ope := range opes {
+				entries = append(entries, helpFormatter.Entry{
+					Key:         ope.Key + " (" + ope.ValueType + ")",
+					Description: ope.Description,
+					Annotations: ope.Annotations,
+				})
+			}
+			return helpFormatter.Format(entries, "    ", width)
+		},
+	}
+}
+
+func helpOption(opt argumentOption.Option) HelpOption {
+	return HelpOption{
+		Keys:          opt.String(),
+		LongKeys:      opt.GetLongKeys(),
+		ShortKeys:     opt.GetShortKeys(),
+		ValueType:     opt.ValueType,
+		ValueName:     opt.GetValueName(),
+		ValueOptional: opt.ValueOptional,
+		ValueHint:     opt.ValueHint,
+		Description:   opt.Description,
+		Group:         opt.Group,
+		Constraint:    validator.Constraint(opt.Validator, opt.ValidatorParam),
+		Choices:       validator.Choices(opt.Validator, opt.ValidatorParam),
+		Required:      opt.Required,
+		Deprecated:    opt.Deprecated,
+		ReplacedBy:    opt.ReplacedBy,
+		Default:       formatDefault(opt.DefaultValue),
+		Annotations:   opt.Annotations(),
+	}
+}
+
+func helpOperand(ope argumentOperand.Operand) HelpOperand {
+	return HelpOperand{
+		Key:         ope.Key,
+		ValueType:   ope.ValueType,
+		ValueHint:   ope.ValueHint,
+		Description: ope.Description,
+		Constraint:  validator.Constraint(ope.Validator, ope.ValidatorParam),
+		Choices:     validator.Choices(ope.Validator, ope.ValidatorParam),
+		Required:    ope.Required,
+		Deprecated:  ope.Deprecated,
+		ReplacedBy:  ope.ReplacedBy,
+		Default:     formatDefault(ope.DefaultValue),
+		Annotations: ope.Annotations(),
+	}
+}
+
+/*
+ * Private Methods
+ */
+
+// This function groups options into sections.
+// Options without Group come first, then the added groups in order,
+// then the groups which are not added in order of appearance.
+func (arg Args) helpGroups(opts []HelpOption) []HelpGroup {
+	groups := []HelpGroup{{Name: "Options"}}
+	for _, group := range arg.groups {
+		groups = append(groups, HelpGroup{Name: group.Name, Description: group.Description})
+	}
+
+	for _, opt := range opts {
+		index := 0
+		if opt.Group != "" {
+			for index = 1; index < len(groups); index++ {
+				if groups[index].Name == opt.Group {
+					break
+				}
+			}
+			if index == len(groups) {
+				groups = append(groups, HelpGroup{Name: opt.Group})
+			}
+		}
+		groups[index].Options = append(groups[index].Options, opt)
+	}
+
+	// Remove empty sections
+	nonEmptyGroups := []HelpGroup{}
+	for _, group := range groups {
+		if len(group.Options) != 0 {
+			nonEmptyGroups = append(nonEmptyGroups, group)
+		}
+	}
+	return nonEmptyGroups
+}
+
+/*
+ * Public Methods
+ */
+
+// HelpData returns the data given to HelpTemplate.
+func (arg Args) HelpData() HelpData {
+	data := HelpData{
+		Name:        arg.programName(),
+		Synopsis:    arg.Synopsis(),
+		Description: arg.Description,
+		Environment: arg.Environment,
+		Examples:    arg.Examples,
+		Epilog:      arg.Epilog,
+		Width:       arg.helpWidth(),
+	}
+	for _, opt := range arg.optionList.GetOptions() {
+		if !opt.Hidden {
+			data.Options = append(data.Options, helpOption(opt))
+		}
+	}
+	data.Groups = arg.helpGroups(data.Options)
+	for _, ope := range arg.operandList.GetOperands() {
+		if !ope.Hidden {
+			data.Operands = append(data.Operands, helpOperand(ope))
+		}
+	}
+	return data
+}
+
+// WriteHelp writes usage rendered by HelpTemplate to w.
+func (arg Args) WriteHelp(w io.Writer) error {
+	text := arg.HelpTemplate
+	if text == "" {
+		text = DefaultHelpTemplate
+	}
+	data := arg.HelpData()
+	tmpl, err := template.New("help").Funcs(helpFuncs(data.Width)).Parse(text)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, data)
+}
+
+func (arg Args) String() string {
+	var buf bytes.Buffer
+	if err := arg.WriteHelp(&buf); err != nil {
+		return err.Error()
+	}
+	return buf.String()
+}
@@ -0,0 +1,186 @@
+package helpFormatter
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mozzzzy/arguments/v2/terminal"
+)
+
+/*
+ * Types
+ */
+
+// Entry is a line of help message, like an option or an operand.
+type Entry struct {
+	Key         string
+	Description string
+	// Annotations like "(required)" follow the description and are never broken.
+	Annotations []string
+}
+
+type runeRange struct {
+	first rune
+	last  rune
+}
+
+/*
+ * Constants and Package Scope Variables
+ */
+
+const DefaultWidth = 80
+
+// If the description column is narrower than this, descriptions start at the next line.
+const minDescriptionWidth = 24
+
+// East Asian wide and fullwidth characters, and emoji.
+var wideRanges = []runeRange{
+	{0x1100, 0x115F},
+	{0x2E80, 0x303E},
+	{0x3041, 0x33FF},
+	{0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF},
+	{0xA000, 0xA4CF},
+	{0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF},
+	{0xFE30, 0xFE4F},
+	{0xFF00, 0xFF60},
+	{0xFFE0, 0xFFE6},
+	{0x1F300, 0x1F64F},
+	{0x1F900, 0x1F9FF},
+	{0x20000, 0x2FFFD},
+	{0x30000, 0x3FFFD},
+}
+
+/*
+ * Package Private Functions
+ */
+
+func spaces(count int) string {
+	if count <= 0 {
+		return ""
+	}
+	return strings.Repeat(" ", count)
+}
+
+/*
+ * Public Functions
+ */
+
+// RuneWidth returns the number of columns which r occupies in terminals.
+func RuneWidth(r rune) int {
+	if r == 0 || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || unicode.Is(unicode.Cf, r) {
+		return 0
+	}
+	for _, wide := range wideRanges {
+		if wide.first <= r && r <= wide.last {
+			return 2
+		}
+	}
+	return 1
+}
+
+// StringWidth returns the number of columns which str occupies in terminals.
+func StringWidth(str string) int {
+	width := 0
+	for _, r := range str {
+		width += RuneWidth(r)
+	}
+	return width
+}
+
+// DetectWidth returns the width of the terminal.
+// COLUMNS environment variable is preferred, then the size of the terminal attached to stdout.
+// If neither is available, DefaultWidth is returned.
+func DetectWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	if columns := terminal.Width(os.Stdout); columns > 0 {
+		return columns
+	}
+	return DefaultWidth
+}
+
+// Wrap joins words with spaces into lines not wider than width.
+// A word wider than width is put on its own line.
+func Wrap(words []string, width int) []string {
+	lines := []string{}
+	line := ""
+	for _, word := range words {
+		if line != "" && StringWidth(line)+1+StringWidth(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Format aligns entries like "<indent><key> : <description> <annotations>".
+// If width is positive, descriptions are wrapped with hanging indentation.
+func Format(entries []Entry, indent string, width int) string {
+	keyWidth := 0
+	for _, entry := range entries {
+		if StringWidth(entry.Key) > keyWidth {
+			keyWidth = StringWidth(entry.Key)
+		}
+	}
+	// The column where descriptions start, after " : "
+	descColumn := StringWidth(indent) + keyWidth + 3
+
+	str := ""
+	for _, entry := range entries {
+		str += indent + entry.Key
+		separator := " "
+		if entry.Description != "" {
+			separator = " : "
+		}
+		if entry.Description == "" && len(entry.Annotations) == 0 {
+			str += "\n"
+			continue
+		}
+		str += spaces(keyWidth - StringWidth(entry.Key))
+
+		// Without wrapping
+		if width <= 0 {
+			texts := []string{}
+			if entry.Description != "" {
+				texts = append(texts, entry.Description)
+			}
+			texts = append(texts, entry.Annotations...)
+			str += separator + strings.Join(texts, " ") + "\n"
+			continue
+		}
+
+		words := append(strings.Fields(entry.Description), entry.Annotations...)
+		hangingIndent := descColumn
+		if width-descColumn < minDescriptionWidth {
+			// Too narrow. Start descriptions at the next line.
+			hangingIndent = StringWidth(indent) + 4
+			str += "\n" + spaces(hangingIndent)
+			separator = ""
+		}
+		for index, line := range Wrap(words, width-hangingIndent) {
+			if index == 0 {
+				str += separator + line
+				continue
+			}
+			str += "\n" + spaces(hangingIndent) + line
+		}
+		str += "\n"
+	}
+	return str
+}
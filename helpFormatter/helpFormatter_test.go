@@ -0,0 +1,74 @@
+package helpFormatter_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mozzzzy/arguments/v2/helpFormatter"
+)
+
+/*
+ * Functions
+ */
+
+func Match(t *testing.T, expected interface{}, actual interface{}) {
+	if expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+/*
+ * Tests
+ */
+
+func TestStringWidth(t *testing.T) {
+	Match(t, 4, helpFormatter.StringWidth("名前"))
+	Match(t, 4, helpFormatter.StringWidth("name"))
+	Match(t, 1, helpFormatter.StringWidth("é"))
+}
+
+func TestWrap(t *testing.T) {
+	words := strings.Fields("some option with a looooooooooong description")
+	Match(t, "some option|with a|looooooooooong|description",
+		strings.Join(helpFormatter.Wrap(words, 11), "|"))
+	Match(t, 0, len(helpFormatter.Wrap([]string{}, 11)))
+}
+
+func TestDetectWidth(t *testing.T) {
+	columns, ok := os.LookupEnv("COLUMNS")
+	if ok {
+		defer os.Setenv("COLUMNS", columns)
+	} else {
+		defer os.Unsetenv("COLUMNS")
+	}
+	os.Setenv("COLUMNS", "120")
+	Match(t, 120, helpFormatter.DetectWidth())
+}
+
+func TestFormat(t *testing.T) {
+	t.Run("No description", func(t *testing.T) {
+		entries := []helpFormatter.Entry{
+			{Key: "--name string", Annotations: []string{"(required)"}},
+			{Key: "-v", Description: "verbose."},
+		}
+		for _, width := range []int{-1, 50} {
+			Match(t,
+				"  --name string (required)\n  -v            : verbose.\n",
+				helpFormatter.Format(entries, "  ", width))
+		}
+	})
+
+	t.Run("Wrap", func(t *testing.T) {
+		entries := []helpFormatter.Entry{
+			{Key: "-n int", Description: "number of retries.", Annotations: []string{"(default: 3)"}},
+		}
+		Match(t,
+			"  -n int : number of retries.\n           (default: 3)\n",
+			helpFormatter.Format(entries, "  ", 40))
+		// Too narrow for descriptions after keys
+		Match(t,
+			"  -n int\n      number of retries.\n      (default: 3)\n",
+			helpFormatter.Format(entries, "  ", 30))
+	})
+}
@@ -0,0 +1,197 @@
+package arguments
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+/*
+ * Types
+ */
+
+// ManHeader is the title line (.TH) of man page.
+type ManHeader struct {
+	// Section of the manual. "1" is used if empty.
+	Section string
+	// Date of the last change, like "2020-02-22"
+	Date string
+	// Source of the command, like "mytool 1.2.3"
+	Source string
+	// Title of the manual, like "User Commands"
+	Manual string
+}
+
+/*
+ * Constants and Package Scope Variables
+ */
+
+var roffReplacer = strings.NewReplacer(
+	`\`, `\e`,
+	`-`, `\-`,
+)
+
+/*
+ * Package Private Functions
+ */
+
+// This function escapes text for roff.
+// Lines starting with "." or "'" are not treated as requests.
+func roffEscape(text string) string {
+	text = roffReplacer.Replace(text)
+	if strings.HasPrefix(text, ".") || strings.HasPrefix(text, "'") {
+		text = `\&` + text
+	}
+	return text
+}
+
+// This function returns the paragraph of text and annotations.
+// If both of them are empty, an empty string is returned.
+func roffParagraph(text string, annotations []string) string {
+	words := append(strings.Fields(text), annotations...)
+	return roffEscape(strings.Join(words, " "))
+}
+
+func roffBold(text string) string {
+	return `\fB` + roffEscape(text) + `\fR`
+}
+
+func roffItalic(text string) string {
+	return `\fI` + roffEscape(text) + `\fR`
+}
+
+// This function returns the tag of option like "\fB\-\-output\fR, \fB\-o\fR \fIstring\fR".
+func roffOptionTag(opt HelpOption) string {
+	var keys []string
+	for _, longKey := range opt.LongKeys {
+		keys = append(keys, roffBold("--"+longKey))
+	}
+	if opt.ValueType != "" && opt.ValueOptional {
+		keys[len(keys)-1] += "[=" + roffItalic(opt.ValueName) + "]"
+	}
+	for _, shortKey := range opt.ShortKeys {
+		keys = append(keys, roffBold("-"+shortKey))
+	}
+	tag := strings.Join(keys, ", ")
+	if opt.ValueType != "" && !opt.ValueOptional {
+		tag += " " + roffItalic(opt.ValueName)
+	}
+	return tag
+}
+
+/*
+ * Public Methods
+ */
+
+// WriteManPage writes man page in roff man(7) format.
+// NAME uses the first line of Description, and DESCRIPTION uses the whole Description.
+func (arg Args) WriteManPage(w io.Writer, header ManHeader) error {
+	data := arg.HelpData()
+	if header.Section == "" {
+		header.Section = "1"
+	}
+
+	buf := bufio.NewWriter(w)
+	// Empty lines are skipped, because they are blank lines in roff.
+	line := func(str string) {
+		if str != "" {
+			buf.WriteString(str + "\n")
+		}
+	}
+
+	line(`.TH "` + roffEscape(strings.ToUpper(data.Name)) + `" "` + roffEscape(header.Section) +
+		`" "` + roffEscape(header.Date) + `" "` + roffEscape(header.Source) +
+		`" "` + roffEscape(header.Manual) + `"`)
+
+	// NAME
+	line(".SH NAME")
+	summary := strings.SplitN(strings.TrimSpace(data.Description), "\n", 2)[0]
+	if summary == "" {
+		line(roffEscape(data.Name))
+	} else {
+		line(roffEscape(data.Name) + ` \- ` + roffEscape(summary))
+	}
+
+	// SYNOPSIS
+	line(".SH SYNOPSIS")
+	synopsis := strings.TrimPrefix(data.Synopsis, data.Name)
+	line(roffBold(data.Name) + roffEscape(synopsis))
+
+	// DESCRIPTION
+	if data.Description != "" {
+		line(".SH DESCRIPTION")
+		for index, paragraph := range strings.Split(strings.TrimSpace(data.Description), "\n\n") {
+			if index != 0 {
+				line(".PP")
+			}
+			line(roffParagraph(paragraph, nil))
+		}
+	}
+
+	// OPTIONS
+	if len(data.Options) != 0 {
+		line(".SH OPTIONS")
+		for index, group := range data.Groups {
+			// Options without group are not in a subsection.
+			if index != 0 || group.Name != "Options" {
+				line(".SS " + roffEscape(group.Name))
+			}
+			if group.Description != "" {
+				line(roffParagraph(group.Description, nil))
+			}
+			for _, opt := range group.Options {
+				line(".TP")
+				line(roffOptionTag(opt))
+				line(roffParagraph(opt.Description, opt.Annotations))
+			}
+		}
+	}
+
+	// ARGUMENTS
+	if len(data.Operands) != 0 {
+		line(".SH ARGUMENTS")
+		for _, ope := range data.Operands {
+			line(".TP")
+			line(roffItalic(ope.Key) + " (" + roffEscape(ope.ValueType) + ")")
+			line(roffParagraph(ope.Description, ope.Annotations))
+		}
+	}
+
+	// ENVIRONMENT
+	if len(data.Environment) != 0 {
+		line(".SH ENVIRONMENT")
+		for _, env := range data.Environment {
+			line(".TP")
+			line(roffBold(env.Name))
+			line(roffParagraph(env.Description, nil))
+		}
+	}
+
+	// EXAMPLES
+	if len(data.Examples) != 0 {
+		line(".SH EXAMPLES")
+		for _, example := range data.Examples {
+			line(".PP")
+			if example.Description != "" {
+				line(roffParagraph(example.Description, nil))
+			}
+			line(".RS 4")
+			line(".nf")
+			line(`\fB$ ` + roffEscape(example.Command) + `\fR`)
+			line(".fi")
+			line(".RE")
+		}
+	}
+
+	// Epilog
+	if data.Epilog != "" {
+		line(".SH NOTES")
+		line(roffParagraph(data.Epilog, nil))
+	}
+
+	return buf.Flush()
+}
@@ -7,8 +7,11 @@ package operandList
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/mozzzzy/arguments/v2/argumentOperand"
+	"github.com/mozzzzy/arguments/v2/helpFormatter"
+	"github.com/mozzzzy/arguments/v2/source"
 )
 
 /*
@@ -33,7 +36,7 @@ func (opeList OperandList) findOpeByKey(key string) (*argumentOperand.Operand, e
 			return &opeList.operands[index], nil
 		}
 	}
-	return nil, errors.New("Specified operand not found.")
+	return nil, errors.New(fmt.Sprintf("Specified operand \"%v\" not found.", key))
 }
 
 /*
@@ -45,6 +48,10 @@ func (opeList *OperandList) AddOperand(newOpe argumentOperand.Operand) error {
 	if err != nil {
 		return err
 	}
+	if _, err := opeList.findOpeByKey(validatedOpe.Key); err == nil {
+		return errors.New(
+			fmt.Sprintf("Operand \"%v\" is already added.", validatedOpe.Key))
+	}
 	opeList.operands = append(opeList.operands, *validatedOpe)
 	return nil
 }
@@ -87,6 +94,16 @@ func (opeList OperandList) GetOpe(key string) (argumentOperand.Operand, error) {
 	return *opePtr, err
 }
 
+// This function records where the value of the operand comes from.
+func (opeList *OperandList) SetSource(key string, src source.Source) error {
+	opePtr, err := opeList.findOpeByKey(key)
+	if err != nil {
+		return err
+	}
+	opePtr.Source = src
+	return nil
+}
+
 func (opeList OperandList) Get(key string) (interface{}, error) {
 	// Find ope by keys
 	opePtr, err := opeList.findOpeByKey(key)
@@ -133,6 +150,19 @@ func (opeList OperandList) IsSet(key string) bool {
 	return ope.Set
 }
 
+// This function returns copies of the registered operands.
+// This prevents caller to modify original operand data in opeList.
+func (opeList OperandList) GetOperands() []argumentOperand.Operand {
+	opes := make([]argumentOperand.Operand, len(opeList.operands))
+	copy(opes, opeList.operands)
+	return opes
+}
+
+// This function returns a copy of the list, whose values can be set without modifying opeList.
+func (opeList OperandList) Clone() OperandList {
+	return OperandList{operands: opeList.GetOperands()}
+}
+
 func (opeList OperandList) Validate() error {
 	for _, ope := range opeList.operands {
 		if err := ope.Validate(); err != nil {
@@ -158,80 +188,46 @@ func (opeList OperandList) GetOpeKeys() []string {
 	return keys
 }
 
-func (opeList OperandList) String() string {
-	str := ""
-	if len(opeList.operands) == 0 {
-		return str
-	}
-
-	str = "  Operands\n"
-	indent := "    "
-
-	var opeKeys []string
-	for _, operand := range opeList.operands {
-		opeKeys = append(opeKeys, operand.Key)
-	}
-
-	for i, operand := range opeList.operands {
-		opeKeys[i] += " (" + operand.ValueType + ")"
-	}
-
-	maxKeyLen := getMaxStrLen(opeKeys)
-
-	for index, operand := range opeList.operands {
-		str += indent
-		// key and value
-		str += opeKeys[index]
-		// space between value and description
-		for i := 0; i < maxKeyLen-len(opeKeys[index]); i++ {
-			str += " "
-		}
-
-		// description
-		if operand.Description != "" {
-			str += " : "
-			str += operand.Description
-		}
-		// required
-		if operand.Required {
-			str += " (required)"
-		}
-		// default value
-		if operand.DefaultValue == nil {
-			str += "\n"
+// This function returns operands in usage synopsis, like "<src> [dst]".
+// Hidden and deprecated operands are not shown.
+func (opeList OperandList) Synopsis() string {
+	var strs []string
+	for _, ope := range opeList.operands {
+		if ope.Hidden || ope.Deprecated {
 			continue
 		}
-		switch operand.ValueType {
-		case "string":
-			defaultValueStr, ok := operand.DefaultValue.(string)
-			if ok {
-				str += fmt.Sprintf(" (default: \"%v\")", defaultValueStr)
-			}
-		case "int":
-			defaultValueInt, ok := operand.DefaultValue.(int)
-			if ok {
-				str += fmt.Sprintf(" (default: %v)", defaultValueInt)
-			}
-		}
-		str += "\n"
+		strs = append(strs, ope.Synopsis())
 	}
-	return str
+	return strings.Join(strs, " ")
 }
 
+func (opeList OperandList) String() string {
+	return opeList.Format(0)
+}
 
-
-/*
- * Private Functions
- */
-
-func getMaxStrLen(strs []string) int {
-	maxLen := 0
-	for _, str := range strs {
-		if len(str) > maxLen {
-			maxLen = len(str)
+// This function returns the help message of operands.
+// If width is positive, descriptions are wrapped to fit in width.
+func (opeList OperandList) Format(width int) string {
+	str := ""
+	var entries []helpFormatter.Entry
+	for _, operand := range opeList.operands {
+		if operand.Hidden {
+			continue
 		}
+		entries = append(entries, helpFormatter.Entry{
+			Key:         operand.Key + " (" + operand.ValueType + ")",
+			Description: operand.Description,
+			Annotations: operand.Annotations(),
+		})
 	}
-	return maxLen
+	if len(entries) == 0 {
+		return str
+	}
+
+	str = "  Operands\n"
+	indent := "    "
+	str += helpFormatter.Format(entries, indent, width)
+	return str
 }
 
 /*
@@ -11,6 +11,9 @@ import (
 	"strings"
 
 	"github.com/mozzzzy/arguments/v2/argumentOption"
+	"github.com/mozzzzy/arguments/v2/helpFormatter"
+	"github.com/mozzzzy/arguments/v2/source"
+	"github.com/mozzzzy/arguments/v2/suggestion"
 )
 
 /*
@@ -49,11 +52,13 @@ func (optList OptionList) findOptByLongKey(longKey string) (*argumentOption.Opti
 	}
 
 	for index := 0; index < len(optList.options); index++ {
-		if optList.options[index].LongKey == longKey {
-			return &optList.options[index], nil
+		for _, key := range optList.options[index].GetLongKeys() {
+			if key == longKey {
+				return &optList.options[index], nil
+			}
 		}
 	}
-	return nil, errors.New("Specified option not found.")
+	return nil, errors.New(fmt.Sprintf("Specified option \"--%v\" not found.", longKey))
 }
 
 func (optList OptionList) findOptByShortKey(shortKey string) (*argumentOption.Option, error) {
@@ -62,11 +67,13 @@ func (optList OptionList) findOptByShortKey(shortKey string) (*argumentOption.Op
 	}
 
 	for index := 0; index < len(optList.options); index++ {
-		if optList.options[index].ShortKey == shortKey {
-			return &optList.options[index], nil
+		for _, key := range optList.options[index].GetShortKeys() {
+			if key == shortKey {
+				return &optList.options[index], nil
+			}
 		}
 	}
-	return nil, errors.New("Specified option not found.")
+	return nil, errors.New(fmt.Sprintf("Specified option \"-%v\" not found.", shortKey))
 }
 
 /*
@@ -78,7 +85,27 @@ func (optList *OptionList) AddOption(newOpt argumentOption.Option) error {
 	if err != nil {
 		return err
 	}
+	// Keys and aliases must not collide with the ones of registered options
+	for _, longKey := range validatedOpt.GetLongKeys() {
+		if opt, err := optList.findOptByLongKey(longKey); err == nil {
+			return errors.New(
+				fmt.Sprintf("Long key \"--%v\" is already used by option %v.", longKey, opt))
+		}
+	}
+	for _, shortKey := range validatedOpt.GetShortKeys() {
+		if opt, err := optList.findOptByShortKey(shortKey); err == nil {
+			return errors.New(
+				fmt.Sprintf("Short key \"-%v\" is already used by option %v.", shortKey, opt))
+		}
+	}
 	optList.options = append(optList.options, *validatedOpt)
+	if validatedOpt.FileKey == "" {
+		return nil
+	}
+	if err := optList.AddOption(validatedOpt.FileOption()); err != nil {
+		optList.options = optList.options[:len(optList.options)-1]
+		return err
+	}
 	return nil
 }
 
@@ -107,7 +134,7 @@ func (optList *OptionList) Set(key string, value interface{}) error {
 		return errors.New(msg)
 	}
 	optPtr.Set = true
-	if !optPtr.ValueRequired() || value == nil {
+	if !optPtr.TakesValue() || value == nil {
 		return nil
 	}
 	return optPtr.SetValue(value)
@@ -126,6 +153,16 @@ func (optList OptionList) GetOpt(key string) (argumentOption.Option, error) {
 	return *optPtr, err
 }
 
+// This function records where the value of the option comes from.
+func (optList *OptionList) SetSource(key string, src source.Source) error {
+	optPtr, err := optList.findOptByKey(key)
+	if err != nil {
+		return err
+	}
+	optPtr.Source = src
+	return nil
+}
+
 func (optList OptionList) Get(key string) (interface{}, error) {
 	// Find opt by long keys
 	optPtr, err := optList.findOptByKey(key)
@@ -133,10 +170,33 @@ func (optList OptionList) Get(key string) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	// The value of a sensitive option is returned only by GetSecret.
+	if optPtr.Sensitive {
+		return nil, errors.New(
+			fmt.Sprintf("Value of option \"%v\" is sensitive.", key))
+	}
 	// If requested option and its default value are not set, return error
 	return optPtr.GetValue()
 }
 
+// This function returns the value of a sensitive option.
+// The value of other options can't be got by this function.
+func (optList OptionList) GetSecret(key string) (string, error) {
+	optPtr, err := optList.findOptByKey(key)
+	if err != nil {
+		return "", err
+	}
+	if !optPtr.Sensitive {
+		return "", errors.New(
+			fmt.Sprintf("Option \"%v\" is not sensitive.", key))
+	}
+	value, err := optPtr.GetValue()
+	if err != nil {
+		return "", err
+	}
+	return value.(string), nil
+}
+
 func (optList OptionList) GetInt(key string) (int, error) {
 	var zeroVal int
 	value, err := optList.Get(key)
@@ -172,6 +232,19 @@ func (optList OptionList) IsSet(key string) bool {
 	return opt.Set
 }
 
+// This function returns copies of the registered options.
+// This prevents caller to modify original option data in optList.
+func (optList OptionList) GetOptions() []argumentOption.Option {
+	opts := make([]argumentOption.Option, len(optList.options))
+	copy(opts, optList.options)
+	return opts
+}
+
+// This function returns a copy of the list, whose values can be set without modifying optList.
+func (optList OptionList) Clone() OptionList {
+	return OptionList{options: optList.GetOptions()}
+}
+
 func (optList OptionList) Validate() error {
 	for _, opt := range optList.options {
 		if err := opt.Validate(); err != nil {
@@ -208,57 +281,121 @@ func (optList OptionList) GetOptKeys() []string {
 	return keys
 }
 
-func (optList OptionList) String() string {
-	str := ""
-	if len(optList.options) == 0 {
-		return str
+// This function returns every key with its prefix, like "--<long key>" and "-<short key>"
+// Keys of hidden and deprecated options are not returned.
+func (optList OptionList) GetKeys() []string {
+	keys := []string{}
+	for _, opt := range optList.options {
+		if opt.Hidden || opt.Deprecated {
+			continue
+		}
+		for _, longKey := range opt.GetLongKeys() {
+			keys = append(keys, "--"+longKey)
+		}
+		for _, shortKey := range opt.GetShortKeys() {
+			keys = append(keys, "-"+shortKey)
+		}
 	}
+	return keys
+}
 
-	str += "  Options\n"
-	indent := "    "
+// This function returns "--<long key>" of options whose long key starts with the prefix.
+func (optList OptionList) GetLongKeysWithPrefix(prefix string) []string {
+	// If prefix has prefix "-", remove them
+	for ; strings.HasPrefix(prefix, "-"); prefix = prefix[1:] {
+	}
 
-	var optStrs []string
+	// Each option appears only once even if several of its aliases match.
+	keys := []string{}
 	for _, opt := range optList.options {
-		optStrs = append(optStrs, opt.String())
+		for _, longKey := range opt.GetLongKeys() {
+			if strings.HasPrefix(longKey, prefix) {
+				keys = append(keys, "--"+longKey)
+				break
+			}
+		}
+	}
+	return keys
+}
+
+// This function returns registered keys which are close to the unknown key.
+// Long keys are only compared with long keys and short keys with short keys.
+func (optList OptionList) Suggest(key string, maxDistance int) []string {
+	var candidates []string
+	for _, candidate := range optList.GetKeys() {
+		if isLongOptKey(candidate) == isLongOptKey(key) {
+			candidates = append(candidates, strings.TrimLeft(candidate, "-"))
+		}
+	}
+
+	prefix := "-"
+	if isLongOptKey(key) {
+		prefix = "--"
 	}
-	maxStrLen := getMaxStrLen(optStrs)
+	suggestions := suggestion.Suggest(strings.TrimLeft(key, "-"), candidates, maxDistance)
+	for index := range suggestions {
+		suggestions[index] = prefix + suggestions[index]
+	}
+	return suggestions
+}
 
-	for index, opt := range optList.options {
-		str += indent
-		// key and value
-		str += optStrs[index]
-		// space between value and description
-		for i := 0; i < maxStrLen-len(optStrs[index]); i++ {
-			str += " "
+// This function returns options in usage synopsis, like "[-v] --name string [-i int]".
+// If there are more optional options than maxOptional, they are collapsed into "[options]".
+// Hidden and deprecated options are not shown.
+func (optList OptionList) Synopsis(maxOptional int) string {
+	var opts []argumentOption.Option
+	optionalCount := 0
+	for _, opt := range optList.options {
+		if opt.Hidden || opt.Deprecated {
+			continue
 		}
-		// description
-		if opt.Description != "" {
-			str += " : "
-			str += opt.Description
+		opts = append(opts, opt)
+		if !opt.Required {
+			optionalCount++
 		}
-		// required
+	}
+	collapse := optionalCount > maxOptional
+
+	var strs []string
+	if collapse {
+		strs = append(strs, "[options]")
+	}
+	for _, opt := range opts {
 		if opt.Required {
-			str += " (required)"
+			strs = append(strs, opt.Synopsis())
+		} else if !collapse {
+			strs = append(strs, "["+opt.Synopsis()+"]")
 		}
-		// default value
-		if opt.ValueType == "" || opt.DefaultValue == nil {
-			str += "\n"
+	}
+	return strings.Join(strs, " ")
+}
+
+func (optList OptionList) String() string {
+	return optList.Format(0)
+}
+
+// This function returns the help message of options.
+// If width is positive, descriptions are wrapped to fit in width.
+func (optList OptionList) Format(width int) string {
+	str := ""
+	var entries []helpFormatter.Entry
+	for _, opt := range optList.options {
+		if opt.Hidden {
 			continue
 		}
-		switch opt.ValueType {
-		case "string":
-			defaultValueStr, ok := opt.DefaultValue.(string)
-			if ok {
-				str += fmt.Sprintf(" (default: \"%v\")", defaultValueStr)
-			}
-		case "int":
-			defaultValueInt, ok := opt.DefaultValue.(int)
-			if ok {
-				str += fmt.Sprintf(" (default: %v)", defaultValueInt)
-			}
-		}
-		str += "\n"
+		entries = append(entries, helpFormatter.Entry{
+			Key:         opt.String(),
+			Description: opt.Description,
+			Annotations: opt.Annotations(),
+		})
+	}
+	if len(entries) == 0 {
+		return str
 	}
+
+	str += "  Options\n"
+	indent := "    "
+	str += helpFormatter.Format(entries, indent, width)
 	return str
 }
 
@@ -300,16 +437,6 @@ func isShortOptKey(str string) bool {
 	return true
 }
 
-func getMaxStrLen(strs []string) int {
-	maxLen := 0
-	for _, str := range strs {
-		if len(str) > maxLen {
-			maxLen = len(str)
-		}
-	}
-	return maxLen
-}
-
 /*
  * Public Functions
  */
@@ -0,0 +1,224 @@
+package arguments
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mozzzzy/arguments/v2/completion"
+)
+
+/*
+ * Types
+ */
+
+/*
+ * Constants and Package Scope Variables
+ */
+
+// The body of the script block which completes the current word by $options, $operands and $valueKeys.
+const powerShellCompleter = `
+    # Arguments before the current word without the executed file name
+    $words = @($commandAst.CommandElements | Select-Object -Skip 1 |
+        Where-Object { $_.Extent.StartOffset -lt $cursorPosition } |
+        ForEach-Object { $_.Extent.Text })
+    if ($wordToComplete -ne '') {
+        $words = @($words | Select-Object -SkipLast 1)
+    }
+
+    $target = $null
+    $filter = $wordToComplete
+    $prefix = ''
+    if ($wordToComplete -like '--*=*') {
+        # --<long key>=<value>
+        $key, $filter = $wordToComplete -split '=', 2
+        $prefix = "$key="
+        $target = $options | Where-Object { $_.Names -contains $key -and $_.Value -ne 'none' } | Select-Object -First 1
+        if (-not $target) {
+            return
+        }
+    } elseif ($wordToComplete.StartsWith('-')) {
+        # keys of options
+        foreach ($option in $options) {
+            foreach ($key in $option.Names) {
+                if ($key.StartsWith($wordToComplete)) {
+                    [System.Management.Automation.CompletionResult]::new($key, $key, 'ParameterName', $option.Description)
+                }
+            }
+        }
+        return
+    } else {
+        # value of the previous option, or operand
+        $count = 0
+        for ($index = 0; $index -lt $words.Count; $index++) {
+            if ($valueKeys -contains $words[$index]) {
+                if ($index -eq $words.Count - 1) {
+                    $target = $options | Where-Object { $_.Names -contains $words[$index] } | Select-Object -First 1
+                    if (-not $target) {
+                        return
+                    }
+                }
+                $index++
+            } elseif (-not $words[$index].StartsWith('-')) {
+                $count++
+            }
+        }
+        if (-not $target) {
+            if ($count -ge $operands.Count) {
+                return
+            }
+            $target = $operands[$count]
+        }
+    }
+
+    $candidates = @()
+    $noFileComp = $false
+    if ($target.Dynamic) {
+        # Empty arguments are dropped by PowerShell older than 7.3.
+        $current = $wordToComplete
+        if ($current -eq '' -and $PSVersionTable.PSVersion -lt [version]'7.3') {
+            $current = '""'
+        }
+        $program = $commandAst.CommandElements[0].Extent.Text
+        $lines = @(& $program __COMPLETE__ @words $current 2>$null)
+        if ($lines.Count -eq 0) {
+            return
+        }
+        $directive = [int]$lines[-1].TrimStart(':')
+        if ($directive -band __ERROR__) {
+            return
+        }
+        $noFileComp = ($directive -band __NOFILECOMP__) -ne 0
+        $candidates = @($lines | Select-Object -SkipLast 1 | ForEach-Object {
+            $value, $description = $_ -split "` + "`" + `t", 2
+            @{ Value = $value; Description = $description }
+        })
+    } else {
+        $candidates = @($target.Choices | ForEach-Object { @{ Value = $_; Description = '' } })
+        $noFileComp = $candidates.Count -ne 0
+    }
+
+    $matched = $false
+    foreach ($candidate in $candidates) {
+        if (-not $candidate.Value.StartsWith($filter)) {
+            continue
+        }
+        $text = $prefix + $candidate.Value
+        if ($text -match '\s') {
+            $text = "'" + ($text -replace "'", "''") + "'"
+        }
+        $tooltip = $candidate.Description
+        if (-not $tooltip) {
+            $tooltip = $candidate.Value
+        }
+        [System.Management.Automation.CompletionResult]::new($text, $candidate.Value, 'ParameterValue', $tooltip)
+        $matched = $true
+    }
+    # If nothing is returned, PowerShell completes paths.
+    # CompletionResult can't be empty, so an empty string is returned instead.
+    if (-not $matched -and $noFileComp) {
+        ''
+    }
+`
+
+/*
+ * Package Private Functions
+ */
+
+// This function quotes str with single quotes for PowerShell.
+func powerShellQuote(str string) string {
+	return "'" + strings.Replace(str, "'", "''", -1) + "'"
+}
+
+func powerShellArray(strs []string) string {
+	var quoted []string
+	for _, str := range strs {
+		quoted = append(quoted, powerShellQuote(str))
+	}
+	return "@(" + strings.Join(quoted, ", ") + ")"
+}
+
+func powerShellBool(value bool) string {
+	if value {
+		return "$true"
+	}
+	return "$false"
+}
+
+// This function returns the description shown as the tooltip, which can't be empty.
+func powerShellTooltip(description string, name string) string {
+	if description = completionDescription(description); description != "" {
+		return description
+	}
+	return name
+}
+
+/*
+ * Public Methods
+ */
+
+// WritePowerShellCompletion writes the completion script for PowerShell.
+// Load it by "mytool --completion powershell | Out-String | Invoke-Expression" in $PROFILE.
+// Values with ValueHint are completed as paths by PowerShell.
+func (arg Args) WritePowerShellCompletion(w io.Writer) error {
+	name := arg.programName()
+
+	buf := bufio.NewWriter(w)
+	line := func(str string) {
+		buf.WriteString(str + "\n")
+	}
+
+	line("# powershell completion for " + name)
+	line("")
+	line("Register-ArgumentCompleter -Native -CommandName " + powerShellQuote(name) + " -ScriptBlock {")
+	line("    param($wordToComplete, $commandAst, $cursorPosition)")
+	line("")
+
+	// Value is "required", "optional" or "none".
+	line("    $options = @(")
+	for _, opt := range arg.completionOptions() {
+		var keys []string
+		for _, longKey := range opt.LongKeys {
+			keys = append(keys, "--"+longKey)
+		}
+		for _, shortKey := range opt.ShortKeys {
+			keys = append(keys, "-"+shortKey)
+		}
+		value := "none"
+		if opt.ValueType != "" && opt.ValueOptional {
+			value = "optional"
+		} else if opt.ValueType != "" {
+			value = "required"
+		}
+		line("        @{ Names = " + powerShellArray(keys) +
+			"; Value = " + powerShellQuote(value) +
+			"; Choices = " + powerShellArray(opt.Choices) +
+			"; Dynamic = " + powerShellBool(opt.dynamic) +
+			"; Description = " + powerShellQuote(powerShellTooltip(opt.Description, keys[0])) + " }")
+	}
+	line("    )")
+	line("    $operands = @(")
+	for _, ope := range arg.completionOperands() {
+		line("        @{ Key = " + powerShellQuote(ope.Key) +
+			"; Choices = " + powerShellArray(ope.Choices) +
+			"; Dynamic = " + powerShellBool(ope.dynamic) + " }")
+	}
+	line("    )")
+	// Values of these options are not counted as operands.
+	line("    $valueKeys = " + powerShellArray(arg.valueOptionKeys()))
+
+	body := strings.NewReplacer(
+		"__COMPLETE__", CompleteCommand,
+		"__ERROR__", strconv.Itoa(int(completion.Error)),
+		"__NOFILECOMP__", strconv.Itoa(int(completion.NoFileComp)),
+	).Replace(powerShellCompleter)
+	buf.WriteString(body)
+	line("}")
+
+	return buf.Flush()
+}
@@ -0,0 +1,206 @@
+package arguments
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mozzzzy/arguments/v2/source"
+	"github.com/mozzzzy/arguments/v2/terminal"
+	"github.com/mozzzzy/arguments/v2/validator"
+)
+
+/*
+ * Types
+ */
+
+// This is a value asked by prompt.
+type promptValue struct {
+	// Name shown in prompt, like "--host"
+	name        string
+	description string
+	valueType   string
+	// Useable values shown as a menu
+	choices []string
+	masked  bool
+	// validate is called with the converted value.
+	validate func(value interface{}) error
+}
+
+/*
+ * Private Methods
+ */
+
+func (args Args) promptInput() io.Reader {
+	if args.PromptInput == nil {
+		return os.Stdin
+	}
+	return args.PromptInput
+}
+
+func (args Args) promptOutput() io.Writer {
+	if args.PromptOutput == nil {
+		return os.Stderr
+	}
+	return args.PromptOutput
+}
+
+// This function returns true if missing values should be prompted.
+func (args Args) interactive() bool {
+	if !args.PromptMissing {
+		return false
+	}
+	if args.PromptAlways {
+		return true
+	}
+	file, ok := args.promptInput().(*os.File)
+	return ok && terminal.IsTerminal(file)
+}
+
+// This function reads a line of the input.
+// If masked is true and the input is a terminal, the line is not echoed.
+func (args Args) readPromptLine(reader *bufio.Reader, masked bool) (string, error) {
+	if file, ok := args.promptInput().(*os.File); ok && masked && terminal.IsTerminal(file) {
+		line, err := terminal.ReadPassword(file)
+		// The newline is not echoed either.
+		fmt.Fprintln(args.promptOutput())
+		return line, err
+	}
+	line, err := reader.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// This function asks the value until it is valid.
+// If the input ends, an error is returned.
+func (args Args) ask(reader *bufio.Reader, value promptValue) (interface{}, error) {
+	output := args.promptOutput()
+	for {
+		fmt.Fprint(output, value.name)
+		if value.description != "" {
+			fmt.Fprint(output, " ("+value.description+")")
+		}
+		if len(value.choices) != 0 {
+			fmt.Fprintln(output)
+			for index, choice := range value.choices {
+				fmt.Fprintf(output, "  %v) %v\n", index+1, choice)
+			}
+			fmt.Fprintf(output, "Choose [1-%v]", len(value.choices))
+		}
+		fmt.Fprint(output, ": ")
+
+		line, err := args.readPromptLine(reader, value.masked)
+		if err != nil {
+			return nil, errors.New(
+				fmt.Sprintf("No value is given for %v. %v", value.name, err.Error()))
+		}
+		if !value.masked {
+			line = strings.TrimSpace(line)
+		}
+		if line == "" {
+			fmt.Fprintln(output, "Error: Value is required.")
+			continue
+		}
+
+		// The number of menu is accepted unless it is one of the choices.
+		isChoice := false
+		for _, choice := range value.choices {
+			isChoice = isChoice || choice == line
+		}
+		if number, err := strconv.Atoi(line); err == nil && !isChoice && number >= 1 && number <= len(value.choices) {
+			line = value.choices[number-1]
+		}
+
+		converted, err := convertValue(value.valueType, line)
+		if err != nil {
+			if value.masked {
+				fmt.Fprintf(output, "Error: The value is not %v.\n", value.valueType)
+			} else {
+				fmt.Fprintf(output, "Error: \"%v\" is not %v.\n", line, value.valueType)
+			}
+			continue
+		}
+		if err := value.validate(converted); err != nil {
+			fmt.Fprintln(output, "Error: "+err.Error())
+			continue
+		}
+		return converted, nil
+	}
+}
+
+// This function prompts for the values of required options and operands which are not given.
+// Options without ValueType are not prompted.
+func (args *Args) promptMissing() error {
+	if !args.interactive() {
+		return nil
+	}
+	reader := bufio.NewReader(args.promptInput())
+
+	for _, opt := range args.optionList.GetOptions() {
+		if !opt.Required || opt.Set || !opt.TakesValue() {
+			continue
+		}
+		// opt is a copy, so that the validator can be tried without modifying optionList.
+		opt := opt
+		value, err := args.ask(reader, promptValue{
+			name:        opt.Name(),
+			description: opt.Description,
+			valueType:   opt.ValueType,
+			choices:     validator.Choices(opt.Validator, opt.ValidatorParam),
+			masked:      opt.Sensitive,
+			validate: func(value interface{}) error {
+				opt.Value = value
+				opt.Set = true
+				return opt.Validate()
+			},
+		})
+		if err != nil {
+			return err
+		}
+		key := strings.Fields(opt.Name())[0]
+		if err := args.optionList.Set(key, value); err != nil {
+			return err
+		}
+		if err := args.optionList.SetSource(key, source.Source{Kind: source.Prompt}); err != nil {
+			return err
+		}
+	}
+
+	for _, ope := range args.operandList.GetOperands() {
+		if !ope.Required || ope.Set {
+			continue
+		}
+		ope := ope
+		value, err := args.ask(reader, promptValue{
+			name:        ope.Key,
+			description: ope.Description,
+			valueType:   ope.ValueType,
+			choices:     validator.Choices(ope.Validator, ope.ValidatorParam),
+			validate: func(value interface{}) error {
+				ope.Value = value
+				ope.Set = true
+				return ope.Validate()
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if err := args.operandList.Set(ope.Key, value); err != nil {
+			return err
+		}
+		if err := args.operandList.SetSource(ope.Key, source.Source{Kind: source.Prompt}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,339 @@
+package arguments
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mozzzzy/arguments/v2/argumentOption"
+	"github.com/mozzzzy/arguments/v2/completion"
+	"github.com/mozzzzy/arguments/v2/optionList"
+	"github.com/mozzzzy/arguments/v2/tokenizer"
+)
+
+/*
+ * Types
+ */
+
+// REPL is an interactive shell driven by the definition of Args.
+// Each line is split like shell, parsed by a clone of Args and given to Handler.
+//
+// The following commands are built in.
+//
+//	help    : write usage
+//	history : write History with numbers
+//	!!, !N  : execute the last line or the Nth line of History again.
+//	          Lines with redacted values of sensitive options can't be executed again.
+//	exit    : stop the REPL. "quit" is also available.
+type REPL struct {
+	// Definition of options and operands. Each line is parsed by a clone of it,
+	// so that the values of a line don't remain in the next line.
+	Args Args
+	// Handler is called with the parsed clone for each line. Returning ErrExit stops the REPL.
+	Handler func(args *Args) error
+	// Prompt written before each line. DefaultPrompt is used if empty.
+	Prompt string
+	// Input and Output of the REPL. os.Stdin and os.Stdout are used if nil.
+	Input  io.Reader
+	Output io.Writer
+	// Lines executed so far, oldest first.
+	// Values of sensitive options are redacted, and recalling lines with them is an error.
+	History []string
+	// Max number of lines kept in History. 0 means no limit.
+	HistorySize int
+}
+
+// This is a value of a sensitive option in the tokens of a line.
+type sensitiveValue struct {
+	// Index of the token which has the value
+	index int
+	// Key of the option, like "--token"
+	key string
+	// inline is true if the token is "--<long key>=<value>".
+	inline bool
+}
+
+/*
+ * Constants and Package Scope Variables
+ */
+
+const DefaultPrompt = "> "
+
+// ErrExit is returned by Execute of REPL when "exit" is executed.
+// Handler can also return it to stop the REPL.
+var ErrExit = errors.New("Exit is requested.")
+
+var replCommands = []string{"exit", "help", "history", "quit"}
+
+// Words which are written in History without quotes
+var replPlainWord = regexp.MustCompile(`^[a-zA-Z0-9_@%+=:,./-]+$`)
+
+/*
+ * Private Methods
+ */
+
+func (repl REPL) input() io.Reader {
+	if repl.Input == nil {
+		return os.Stdin
+	}
+	return repl.Input
+}
+
+func (repl REPL) output() io.Writer {
+	if repl.Output == nil {
+		return os.Stdout
+	}
+	return repl.Output
+}
+
+func (repl *REPL) addHistory(line string) {
+	repl.History = append(repl.History, line)
+	if repl.HistorySize > 0 && len(repl.History) > repl.HistorySize {
+		repl.History = repl.History[len(repl.History)-repl.HistorySize:]
+	}
+}
+
+// This function returns the line of History which "!!" or "!N" stands for.
+// ok is false if line is not a history reference.
+func (repl REPL) recall(line string) (recalled string, ok bool, err error) {
+	if !strings.HasPrefix(line, "!") {
+		return "", false, nil
+	}
+	index := len(repl.History)
+	if line != "!!" {
+		number, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return "", false, nil
+		}
+		index = number
+	}
+	if index < 1 || index > len(repl.History) {
+		return "", true, errors.New(fmt.Sprintf("Line %v is not found in history.", line))
+	}
+	return repl.History[index-1], true, nil
+}
+
+// This function returns a clone of Args with the options of AutoHelp and AutoVersion.
+func (repl REPL) definition() (Args, error) {
+	args := repl.Args.Clone()
+	args.Output = repl.output()
+	err := args.addAutoOpts()
+	return args, err
+}
+
+func (repl REPL) newTokenizer() tokenizer.Tokenizer {
+	return tokenizer.Tokenizer{Comments: true, ExpandEnv: repl.Args.ExpandEnv}
+}
+
+// This function returns the values of sensitive options in tokens.
+func (repl REPL) sensitiveValues(tokens []tokenizer.Token) []sensitiveValue {
+	args, err := repl.definition()
+	if err != nil {
+		return nil
+	}
+	values := []sensitiveValue{}
+	for index := 0; index < len(tokens); index++ {
+		word := tokens[index].Value
+		if !optionList.IsOptKey(word) {
+			continue
+		}
+		key := word
+		separator := strings.Index(word, "=")
+		if strings.HasPrefix(word, "--") && separator >= 0 {
+			key = word[:separator]
+		}
+		resolvedKey, err := args.resolveAbbrev(key)
+		if err != nil {
+			continue
+		}
+		opt, err := args.optionList.GetOpt(resolvedKey)
+		if err != nil || !opt.Sensitive {
+			continue
+		}
+		switch {
+		case key != word:
+			values = append(values, sensitiveValue{index: index, key: key, inline: true})
+		case opt.ValueRequired() && index+1 < len(tokens):
+			index++
+			values = append(values, sensitiveValue{index: index, key: key})
+		}
+	}
+	return values
+}
+
+// This function returns line whose values of sensitive options are replaced with
+// argumentOption.Redacted, so that secrets are not kept in History nor echoed.
+// line is returned as it is if it has no sensitive value.
+func (repl REPL) redact(line string) string {
+	// Environment variables are not expanded, so that the line is written as it is typed.
+	tokens, err := tokenizer.Tokenizer{Comments: true}.Tokenize(line)
+	if err != nil {
+		return line
+	}
+	values := repl.sensitiveValues(tokens)
+	if len(values) == 0 {
+		return line
+	}
+	words := []string{}
+	for _, token := range tokens {
+		words = append(words, token.Value)
+	}
+	for _, value := range values {
+		if value.inline {
+			words[value.index] = value.key + "=" + argumentOption.Redacted
+		} else {
+			words[value.index] = argumentOption.Redacted
+		}
+	}
+	for index, word := range words {
+		if !replPlainWord.MatchString(word) {
+			words[index] = shellQuote(word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// This function returns an error if a recalled line has a redacted value of sensitive option,
+// so that argumentOption.Redacted is not used as the value.
+func (repl REPL) checkRedacted(line string) error {
+	tokens, err := tokenizer.Tokenizer{Comments: true}.Tokenize(line)
+	if err != nil {
+		return nil
+	}
+	for _, value := range repl.sensitiveValues(tokens) {
+		redacted := argumentOption.Redacted
+		if value.inline {
+			redacted = value.key + "=" + redacted
+		}
+		if tokens[value.index].Value == redacted {
+			return errors.New(fmt.Sprintf(
+				"The value of %v is redacted in history. Enter the line again with the value.", value.key))
+		}
+	}
+	return nil
+}
+
+/*
+ * Public Methods
+ */
+
+// Run reads lines from Input and executes them until "exit" or the end of Input.
+// Errors of lines are written to Output and the REPL continues.
+func (repl *REPL) Run() error {
+	prompt := repl.Prompt
+	if prompt == "" {
+		prompt = DefaultPrompt
+	}
+	scanner := bufio.NewScanner(repl.input())
+	for {
+		fmt.Fprint(repl.output(), prompt)
+		if !scanner.Scan() {
+			fmt.Fprintln(repl.output())
+			return scanner.Err()
+		}
+		err := repl.Execute(scanner.Text())
+		switch {
+		case errors.Is(err, ErrExit):
+			return nil
+		case err == nil, errors.Is(err, ErrHelp), errors.Is(err, ErrVersion):
+		default:
+			fmt.Fprintln(repl.output(), "Error: "+err.Error())
+		}
+	}
+}
+
+// Execute executes a line. The line doesn't include the executed file name.
+func (repl *REPL) Execute(line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil
+	}
+	recalled, ok, err := repl.recall(line)
+	if err != nil {
+		return err
+	}
+	if ok {
+		line = recalled
+		fmt.Fprintln(repl.output(), repl.redact(line))
+		if err := repl.checkRedacted(line); err != nil {
+			return err
+		}
+	}
+	repl.addHistory(repl.redact(line))
+
+	switch line {
+	case "exit", "quit":
+		return ErrExit
+	case "help":
+		args, err := repl.definition()
+		if err != nil {
+			return err
+		}
+		return args.WriteHelp(repl.output())
+	case "history":
+		for index, historyLine := range repl.History {
+			fmt.Fprintf(repl.output(), "%5d  %v\n", index+1, historyLine)
+		}
+		return nil
+	}
+
+	tokens, err := repl.newTokenizer().Tokenize(line)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+	argv := []string{repl.Args.programName()}
+	for _, token := range tokens {
+		argv = append(argv, token.Value)
+	}
+	args, err := repl.definition()
+	if err != nil {
+		return err
+	}
+	if err := args.ParseArgs(argv); err != nil {
+		return err
+	}
+	if repl.Handler == nil {
+		return nil
+	}
+	return repl.Handler(&args)
+}
+
+// Complete returns the candidates of the last word of the partial line,
+// by the same options and operands as completion scripts.
+// It can be used from line editors.
+func (repl REPL) Complete(line string) ([]completion.Candidate, completion.Directive) {
+	tokens, err := repl.newTokenizer().Tokenize(line)
+	if err != nil {
+		return nil, completion.Error
+	}
+	words := []string{}
+	for _, token := range tokens {
+		words = append(words, token.Value)
+	}
+	// The line ending with a space starts a new word.
+	if len(words) == 0 || strings.HasSuffix(line, " ") {
+		words = append(words, "")
+	}
+
+	args, err := repl.definition()
+	if err != nil {
+		return nil, completion.Error
+	}
+	candidates, directive := args.complete(words)
+	if len(words) == 1 && !strings.HasPrefix(words[0], "-") {
+		candidates = append(completion.Filter(completion.Values(replCommands), words[0]), candidates...)
+	}
+	return candidates, directive
+}
@@ -0,0 +1,144 @@
+package arguments
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/mozzzzy/arguments/v2/source"
+	"github.com/mozzzzy/arguments/v2/tokenizer"
+)
+
+/*
+ * Types
+ */
+
+// ResponseFileError is returned when a response file can't be expanded.
+// Path and Line are the position of the bad token, or of "@file" which can't be read.
+type ResponseFileError struct {
+	Path string
+	Line int
+	Err  error
+}
+
+/*
+ * Constants and Package Scope Variables
+ */
+
+/*
+ * Package Private Functions
+ */
+
+func isResponseFile(arg string) bool {
+	return len(arg) > 1 && arg[0] == '@'
+}
+
+// This function expands "@file" arguments in argv except the executed file name.
+// The argument following an argument for which isFileValueKey returns true is not expanded,
+// because it is the value of an option with ValueFromFile.
+// It also returns where each of the expanded arguments comes from.
+func expandResponseFiles(argv []string, isFileValueKey func(arg string) bool) ([]string, []source.Source, error) {
+	if len(argv) == 0 {
+		return argv, nil, nil
+	}
+	expanded := []string{argv[0]}
+	sources := []source.Source{{Kind: source.Argv, Index: 0}}
+	for index, arg := range argv[1:] {
+		if !isResponseFile(arg) || isFileValueKey(expanded[len(expanded)-1]) {
+			expanded = append(expanded, arg)
+			sources = append(sources, source.Source{Kind: source.Argv, Index: index + 1})
+			continue
+		}
+		words, wordSources, err := readResponseFile(arg[1:], nil, isFileValueKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		expanded = append(expanded, words...)
+		sources = append(sources, wordSources...)
+	}
+	return expanded, sources, nil
+}
+
+// This function returns the arguments written in the response file of path and their positions.
+// Response files in it are expanded recursively, and their relative paths are resolved
+// from the directory of the file including them.
+// included is the absolute paths of the files including this file, which is used to detect cycles.
+func readResponseFile(path string, included []string, isFileValueKey func(arg string) bool) ([]string, []source.Source, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, includedPath := range included {
+		if includedPath == absPath {
+			return nil, nil, errors.New(
+				fmt.Sprintf("Response file \"%v\" includes itself.", path))
+		}
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := tokenizer.Tokenizer{Comments: true}.Tokenize(string(data))
+	if err != nil {
+		var syntaxErr *tokenizer.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return nil, nil, &ResponseFileError{Path: path, Line: syntaxErr.Line, Err: errors.New(syntaxErr.Msg)}
+		}
+		return nil, nil, &ResponseFileError{Path: path, Err: err}
+	}
+
+	words := []string{}
+	sources := []source.Source{}
+	for _, token := range tokens {
+		if !isResponseFile(token.Value) || (len(words) != 0 && isFileValueKey(words[len(words)-1])) {
+			words = append(words, token.Value)
+			sources = append(sources, source.Source{Kind: source.ResponseFile, Path: path, Line: token.Line})
+			continue
+		}
+		nestedPath := token.Value[1:]
+		if !filepath.IsAbs(nestedPath) {
+			nestedPath = filepath.Join(filepath.Dir(path), nestedPath)
+		}
+		nestedWords, nestedSources, err := readResponseFile(
+			nestedPath, append(included[:len(included):len(included)], absPath), isFileValueKey)
+		if err != nil {
+			var fileErr *ResponseFileError
+			if errors.As(err, &fileErr) {
+				return nil, nil, err
+			}
+			return nil, nil, &ResponseFileError{Path: path, Line: token.Line, Err: err}
+		}
+		words = append(words, nestedWords...)
+		sources = append(sources, nestedSources...)
+	}
+	return words, sources, nil
+}
+
+// This function adds the position of the argument to err if the argument is written in a response file.
+func locateError(err error, src source.Source) error {
+	if src.Kind != source.ResponseFile {
+		return err
+	}
+	return &ResponseFileError{Path: src.Path, Line: src.Line, Err: err}
+}
+
+/*
+ * Public Methods
+ */
+
+func (err *ResponseFileError) Error() string {
+	if err.Line == 0 {
+		return fmt.Sprintf("%v: %v", err.Path, err.Err.Error())
+	}
+	return fmt.Sprintf("%v:%v: %v", err.Path, err.Line, err.Err.Error())
+}
+
+func (err *ResponseFileError) Unwrap() error {
+	return err.Err
+}
@@ -0,0 +1,64 @@
+package source
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"fmt"
+)
+
+/*
+ * Types
+ */
+
+// Kind is where a value of an option or operand comes from.
+type Kind string
+
+// Source is where a value of an option or operand comes from.
+// Index is the position in argv for Argv. Path and Line are the position in the file
+// for ResponseFile, and Path is the file whose contents is the value for File.
+type Source struct {
+	Kind  Kind   `json:"kind"`
+	Index int    `json:"index,omitempty"`
+	Path  string `json:"path,omitempty"`
+	Line  int    `json:"line,omitempty"`
+}
+
+/*
+ * Constants and Package Scope Variables
+ */
+
+const (
+	// Neither the value nor the default value is set.
+	Unset Kind = "unset"
+	// DefaultValue is used.
+	Default Kind = "default"
+	// The value is given in argv.
+	Argv Kind = "argv"
+	// The value is written in a response file.
+	ResponseFile Kind = "response-file"
+	// The value is the contents of a file, given by FileKey or ValueFromFile.
+	File Kind = "file"
+	// The value is read from the standard input.
+	Stdin Kind = "stdin"
+	// The value is answered to the prompt.
+	Prompt Kind = "prompt"
+)
+
+/*
+ * Public Methods
+ */
+
+// This function returns the source used in messages, like "argv[2]" and "args.txt:3".
+func (src Source) String() string {
+	switch src.Kind {
+	case Argv:
+		return fmt.Sprintf("argv[%v]", src.Index)
+	case ResponseFile:
+		return fmt.Sprintf("%v:%v", src.Path, src.Line)
+	case File:
+		return "file " + src.Path
+	}
+	return string(src.Kind)
+}
@@ -0,0 +1,49 @@
+package source_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mozzzzy/arguments/v2/source"
+)
+
+/*
+ * Functions
+ */
+
+func Match(t *testing.T, expected interface{}, actual interface{}) {
+	if expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+/*
+ * Tests
+ */
+
+func TestString(t *testing.T) {
+	cases := []struct {
+		src      source.Source
+		expected string
+	}{
+		{source.Source{Kind: source.Argv, Index: 2}, "argv[2]"},
+		{source.Source{Kind: source.ResponseFile, Path: "args.txt", Line: 3}, "args.txt:3"},
+		{source.Source{Kind: source.File, Path: "token"}, "file token"},
+		{source.Source{Kind: source.Stdin}, "stdin"},
+		{source.Source{Kind: source.Prompt}, "prompt"},
+		{source.Source{Kind: source.Default}, "default"},
+		{source.Source{Kind: source.Unset}, "unset"},
+	}
+	for _, c := range cases {
+		Match(t, c.expected, c.src.String())
+	}
+}
+
+func TestJSON(t *testing.T) {
+	data, err := json.Marshal(source.Source{Kind: source.Argv, Index: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Fields which don't belong to the kind are omitted.
+	Match(t, `{"kind":"argv","index":2}`, string(data))
+}
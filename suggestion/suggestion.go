@@ -0,0 +1,102 @@
+package suggestion
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"sort"
+)
+
+/*
+ * Types
+ */
+
+/*
+ * Constants and Package Scope Variables
+ */
+
+// DefaultMaxDistance is used when a caller does not configure its own threshold.
+const DefaultMaxDistance = 2
+
+/*
+ * Package Private Functions
+ */
+
+func min(values ...int) int {
+	minValue := values[0]
+	for _, value := range values[1:] {
+		if value < minValue {
+			minValue = value
+		}
+	}
+	return minValue
+}
+
+/*
+ * Public Functions
+ */
+
+// Distance returns the edit distance between a and b.
+// Insertion, deletion, substitution and transposition of two adjacent
+// characters are counted as one edit.
+func Distance(a, b string) int {
+	ra := []rune(a)
+	rb := []rune(b)
+
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := 0; j <= len(rb); j++ {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+// Suggest returns candidates whose distance from input is at most maxDistance,
+// closest first. A candidate is never suggested when the distance is as long as
+// input itself, so that one-character inputs don't match everything.
+func Suggest(input string, candidates []string, maxDistance int) []string {
+	type scored struct {
+		candidate string
+		distance  int
+	}
+
+	var matches []scored
+	seen := map[string]bool{}
+	for _, candidate := range candidates {
+		if seen[candidate] || candidate == input {
+			continue
+		}
+		seen[candidate] = true
+		distance := Distance(input, candidate)
+		if distance > maxDistance || distance >= len([]rune(input)) {
+			continue
+		}
+		matches = append(matches, scored{candidate, distance})
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].distance < matches[j].distance
+	})
+
+	suggestions := []string{}
+	for _, match := range matches {
+		suggestions = append(suggestions, match.candidate)
+	}
+	return suggestions
+}
@@ -0,0 +1,58 @@
+package suggestion_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mozzzzy/arguments/v2/suggestion"
+)
+
+/*
+ * Functions
+ */
+
+func Match(t *testing.T, expected interface{}, actual interface{}) {
+	if expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+/*
+ * Tests
+ */
+
+func TestDistance(t *testing.T) {
+	cases := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{"verbose", "verbose", 0},
+		{"verbsoe", "verbose", 1},
+		{"verbos", "verbose", 1},
+		{"verbosee", "verbose", 1},
+		{"varbose", "verbose", 1},
+		{"", "abc", 3},
+		{"名前", "名称", 1},
+	}
+	for _, c := range cases {
+		Match(t, c.expected, suggestion.Distance(c.a, c.b))
+		Match(t, c.expected, suggestion.Distance(c.b, c.a))
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	t.Run("Closest first", func(t *testing.T) {
+		candidates := []string{"stop", "start", "stats", "start"}
+		Match(t, "start|stats|stop", strings.Join(suggestion.Suggest("stat", candidates, 2), "|"))
+		Match(t, "start|stats", strings.Join(suggestion.Suggest("stat", candidates, 1), "|"))
+	})
+
+	t.Run("Same as input", func(t *testing.T) {
+		Match(t, 0, len(suggestion.Suggest("stop", []string{"stop"}, 2)))
+	})
+
+	t.Run("Short input", func(t *testing.T) {
+		Match(t, 0, len(suggestion.Suggest("a", []string{"b", "ab"}, 2)))
+	})
+}
@@ -0,0 +1,37 @@
+package terminal
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"errors"
+	"os"
+)
+
+/*
+ * Constants and Package Scope Variables
+ */
+
+// ErrNotSupported is returned by ReadPassword on platforms where echo can't be disabled.
+var ErrNotSupported = errors.New("Disabling echo of terminal is not supported on this platform.")
+
+/*
+ * Public Functions
+ */
+
+// IsTerminal returns true if file is attached to a terminal.
+func IsTerminal(file *os.File) bool {
+	return isTerminal(file.Fd())
+}
+
+// Width returns the columns of the terminal attached to file, or 0 if it is not detected.
+func Width(file *os.File) int {
+	return width(file.Fd())
+}
+
+// ReadPassword reads a line from the terminal attached to file without echo.
+// The newline is not included.
+func ReadPassword(file *os.File) (string, error) {
+	return readPassword(file)
+}
@@ -0,0 +1,19 @@
+//go:build darwin || freebsd
+// +build darwin freebsd
+
+package terminal
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"syscall"
+)
+
+/*
+ * Constants and Package Scope Variables
+ */
+
+const ioctlGetTermios = syscall.TIOCGETA
+const ioctlSetTermios = syscall.TIOCSETA
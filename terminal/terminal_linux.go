@@ -0,0 +1,19 @@
+//go:build linux
+// +build linux
+
+package terminal
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"syscall"
+)
+
+/*
+ * Constants and Package Scope Variables
+ */
+
+const ioctlGetTermios = syscall.TCGETS
+const ioctlSetTermios = syscall.TCSETS
@@ -0,0 +1,30 @@
+//go:build !linux && !darwin && !freebsd
+// +build !linux,!darwin,!freebsd
+
+package terminal
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"os"
+)
+
+/*
+ * Package Private Functions
+ */
+
+// Terminals are not detected on this platform.
+func isTerminal(fd uintptr) bool {
+	return false
+}
+
+// Terminal size is not detected on this platform.
+func width(fd uintptr) int {
+	return 0
+}
+
+func readPassword(file *os.File) (string, error) {
+	return "", ErrNotSupported
+}
@@ -0,0 +1,91 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package terminal
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+/*
+ * Types
+ */
+
+type winsize struct {
+	rows    uint16
+	columns uint16
+	xPixels uint16
+	yPixels uint16
+}
+
+/*
+ * Package Private Functions
+ */
+
+func ioctl(fd uintptr, request uintptr, arg unsafe.Pointer) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, request, uintptr(arg))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func ioctlTermios(fd uintptr, request uintptr, termios *syscall.Termios) error {
+	return ioctl(fd, request, unsafe.Pointer(termios))
+}
+
+func isTerminal(fd uintptr) bool {
+	var termios syscall.Termios
+	return ioctlTermios(fd, ioctlGetTermios, &termios) == nil
+}
+
+// This function returns the columns of the terminal of fd, or 0 if it is not a terminal.
+func width(fd uintptr) int {
+	var size winsize
+	if err := ioctl(fd, uintptr(syscall.TIOCGWINSZ), unsafe.Pointer(&size)); err != nil {
+		return 0
+	}
+	return int(size.columns)
+}
+
+func readPassword(file *os.File) (string, error) {
+	fd := file.Fd()
+	var oldState syscall.Termios
+	if err := ioctlTermios(fd, ioctlGetTermios, &oldState); err != nil {
+		return "", err
+	}
+	newState := oldState
+	newState.Lflag &^= syscall.ECHO
+	newState.Lflag |= syscall.ICANON | syscall.ISIG
+	newState.Iflag |= syscall.ICRNL
+	if err := ioctlTermios(fd, ioctlSetTermios, &newState); err != nil {
+		return "", err
+	}
+	defer ioctlTermios(fd, ioctlSetTermios, &oldState)
+
+	// Read one byte at a time, so that nothing after the newline is consumed.
+	var line []byte
+	buf := make([]byte, 1)
+	for {
+		n, err := file.Read(buf)
+		if n == 1 && buf[0] == '\n' {
+			return string(line), nil
+		}
+		if n == 1 {
+			line = append(line, buf[0])
+		}
+		if err == io.EOF && len(line) != 0 {
+			return string(line), nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
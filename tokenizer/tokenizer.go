@@ -0,0 +1,274 @@
+package tokenizer
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"fmt"
+	"os"
+)
+
+/*
+ * Types
+ */
+
+// Tokenizer splits text into words with the quoting rules of POSIX shell.
+type Tokenizer struct {
+	// Words starting with "#" and the rest of the line are ignored.
+	Comments bool
+	// $NAME and ${NAME} are replaced with the value of environment variable outside single quotes.
+	// The value is not split into words.
+	ExpandEnv bool
+	// Getenv returns the value of environment variable. os.Getenv is used if nil.
+	Getenv func(name string) string
+}
+
+// Token is a word and the position where it starts.
+// Line and Column start from 1, and Column counts characters.
+type Token struct {
+	Value  string
+	Line   int
+	Column int
+}
+
+// SyntaxError is returned when text can't be split, like an unterminated quote.
+// Line and Column are the position of the character which causes the error.
+type SyntaxError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+/*
+ * Constants and Package Scope Variables
+ */
+
+/*
+ * Package Private Functions
+ */
+
+func isSpace(char rune) bool {
+	return char == ' ' || char == '\t' || char == '\n' || char == '\r'
+}
+
+// This function returns true if char can be used in the name of environment variable.
+// Digits can't be the first character.
+func isNameChar(char rune, first bool) bool {
+	return char == '_' ||
+		(char >= 'a' && char <= 'z') ||
+		(char >= 'A' && char <= 'Z') ||
+		(!first && char >= '0' && char <= '9')
+}
+
+/*
+ * Public Functions
+ */
+
+// Split splits text into words without comments.
+func Split(text string) ([]string, error) {
+	tokens, err := Tokenizer{}.Tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+	words := []string{}
+	for _, token := range tokens {
+		words = append(words, token.Value)
+	}
+	return words, nil
+}
+
+/*
+ * Package Private Methods
+ */
+
+func (tok Tokenizer) getenv(name string) string {
+	if tok.Getenv == nil {
+		return os.Getenv(name)
+	}
+	return tok.Getenv(name)
+}
+
+/*
+ * Public Methods
+ */
+
+// Tokenize splits text into tokens.
+//
+//	'...'  : characters are taken as they are.
+//	"..."  : backslash escapes only $, `, ", \ and newline.
+//	\c     : c is taken as it is. Backslash and newline are removed.
+//	$NAME  : the value of environment variable, if ExpandEnv is true. ${NAME} is also available.
+//	         A word which becomes empty by unquoted expansions is dropped, but "$NAME" is kept.
+func (tok Tokenizer) Tokenize(text string) ([]Token, error) {
+	chars := []rune(text)
+	tokens := []Token{}
+
+	line, column := 1, 1
+	// This function returns the current character and moves to the next one.
+	next := func(index *int) rune {
+		char := chars[*index]
+		*index++
+		if char == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+		return char
+	}
+
+	// This function returns the value of the environment variable after "$".
+	// If "$" is not followed by a name, "$" is returned as it is.
+	expand := func(index *int, dollarLine int, dollarColumn int) ([]rune, error) {
+		var name []rune
+		if *index < len(chars) && chars[*index] == '{' {
+			next(index)
+			for {
+				if *index >= len(chars) {
+					return nil, &SyntaxError{Line: dollarLine, Column: dollarColumn, Msg: "Unterminated \"${\"."}
+				}
+				char := next(index)
+				if char == '}' {
+					break
+				}
+				if !isNameChar(char, len(name) == 0) {
+					return nil, &SyntaxError{
+						Line: dollarLine, Column: dollarColumn, Msg: "Invalid name of environment variable."}
+				}
+				name = append(name, char)
+			}
+			if len(name) == 0 {
+				return nil, &SyntaxError{Line: dollarLine, Column: dollarColumn, Msg: "Empty name of environment variable."}
+			}
+			return []rune(tok.getenv(string(name))), nil
+		}
+		for *index < len(chars) && isNameChar(chars[*index], len(name) == 0) {
+			name = append(name, next(index))
+		}
+		if len(name) == 0 {
+			return []rune{'$'}, nil
+		}
+		return []rune(tok.getenv(string(name))), nil
+	}
+
+	var word []rune
+	var token Token
+	inWord := false
+	// A word which has quotes is kept even if it is empty, like "" and "$EMPTY".
+	hasQuotes := false
+	// This function ends the word. Like shells, an empty word made of unquoted expansions is dropped.
+	endWord := func() {
+		if hasQuotes || len(word) != 0 {
+			token.Value = string(word)
+			tokens = append(tokens, token)
+		}
+		word = nil
+		inWord = false
+		hasQuotes = false
+	}
+	for index := 0; index < len(chars); {
+		char := chars[index]
+
+		// end of word
+		if isSpace(char) {
+			if inWord {
+				endWord()
+			}
+			next(&index)
+			continue
+		}
+
+		// comment
+		if !inWord && tok.Comments && char == '#' {
+			for index < len(chars) && chars[index] != '\n' {
+				next(&index)
+			}
+			continue
+		}
+
+		// Backslash and newline are removed without starting a word.
+		if char == '\\' && index+1 < len(chars) && chars[index+1] == '\n' {
+			next(&index)
+			next(&index)
+			continue
+		}
+
+		if !inWord {
+			token = Token{Line: line, Column: column}
+			word = []rune{}
+			inWord = true
+		}
+
+		startLine, startColumn := line, column
+		next(&index)
+		switch char {
+		case '\\':
+			if index >= len(chars) {
+				return nil, &SyntaxError{Line: startLine, Column: startColumn, Msg: "Backslash at the end of text."}
+			}
+			word = append(word, next(&index))
+		case '\'':
+			hasQuotes = true
+			for {
+				if index >= len(chars) {
+					return nil, &SyntaxError{Line: startLine, Column: startColumn, Msg: "Unterminated single quote."}
+				}
+				quoted := next(&index)
+				if quoted == '\'' {
+					break
+				}
+				word = append(word, quoted)
+			}
+		case '"':
+			hasQuotes = true
+			for {
+				if index >= len(chars) {
+					return nil, &SyntaxError{Line: startLine, Column: startColumn, Msg: "Unterminated double quote."}
+				}
+				quoted := next(&index)
+				if quoted == '"' {
+					break
+				}
+				if quoted == '$' && tok.ExpandEnv {
+					value, err := expand(&index, line, column-1)
+					if err != nil {
+						return nil, err
+					}
+					word = append(word, value...)
+					continue
+				}
+				if quoted == '\\' && index < len(chars) {
+					switch chars[index] {
+					case '$', '`', '"', '\\':
+						quoted = next(&index)
+					case '\n':
+						next(&index)
+						continue
+					}
+				}
+				word = append(word, quoted)
+			}
+		case '$':
+			if !tok.ExpandEnv {
+				word = append(word, char)
+				break
+			}
+			value, err := expand(&index, startLine, startColumn)
+			if err != nil {
+				return nil, err
+			}
+			word = append(word, value...)
+		default:
+			word = append(word, char)
+		}
+	}
+	if inWord {
+		endWord()
+	}
+	return tokens, nil
+}
+
+func (err *SyntaxError) Error() string {
+	return fmt.Sprintf("line %v, column %v: %v", err.Line, err.Column, err.Msg)
+}
@@ -0,0 +1,109 @@
+package tokenizer_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mozzzzy/arguments/v2/tokenizer"
+)
+
+/*
+ * Functions
+ */
+
+func Match(t *testing.T, expected interface{}, actual interface{}) {
+	if expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func NoError(t *testing.T, err error) {
+	if err != nil {
+		t.Errorf("Got error: %v", err)
+	}
+}
+
+/*
+ * Tests
+ */
+
+func TestTokenizer(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected []string
+	}{
+		{`deploy --env "prod eu" -n 3`, []string{"deploy", "--env", "prod eu", "-n", "3"}},
+		{`'a "b"' "c 'd'" e\ f`, []string{`a "b"`, `c 'd'`, "e f"}},
+		{`"a\"b\\c\d" '\n'`, []string{`a"b\c\d`, `\n`}},
+		{"a\\\n b '' \"\"", []string{"a", "b", "", ""}},
+		{"a\n  b\t", []string{"a", "b"}},
+		{"", []string{}},
+	}
+	for _, c := range cases {
+		words, err := tokenizer.Split(c.text)
+		NoError(t, err)
+		Match(t, strings.Join(c.expected, "|"), strings.Join(words, "|"))
+		Match(t, len(c.expected), len(words))
+	}
+
+	t.Run("Comments", func(t *testing.T) {
+		tokens, err := tokenizer.Tokenizer{Comments: true}.Tokenize("a # b\nc#d")
+		NoError(t, err)
+		Match(t, 2, len(tokens))
+		Match(t, "c#d", tokens[1].Value)
+		Match(t, 2, tokens[1].Line)
+		Match(t, 1, tokens[1].Column)
+	})
+
+	t.Run("ExpandEnv", func(t *testing.T) {
+		tok := tokenizer.Tokenizer{
+			ExpandEnv: true,
+			Getenv: func(name string) string {
+				return map[string]string{"REGION": "eu west", "N": "3"}[name]
+			},
+		}
+		tokens, err := tok.Tokenize(`--region $REGION "${REGION}-1" '$N' \$N $ $1 x${N}y`)
+		NoError(t, err)
+		var words []string
+		for _, token := range tokens {
+			words = append(words, token.Value)
+		}
+		Match(t, "--region|eu west|eu west-1|$N|$N|$|$1|x3y", strings.Join(words, "|"))
+	})
+
+	t.Run("Empty expansion", func(t *testing.T) {
+		tok := tokenizer.Tokenizer{ExpandEnv: true, Getenv: func(name string) string { return "" }}
+		tokens, err := tok.Tokenize(`a $UNSET ${UNSET} "$UNSET" '' x$UNSET`)
+		NoError(t, err)
+		var words []string
+		for _, token := range tokens {
+			words = append(words, token.Value)
+		}
+		// Unquoted empty expansions are dropped like shells.
+		Match(t, "a|||x", strings.Join(words, "|"))
+		Match(t, 4, len(words))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		for _, c := range []struct {
+			text   string
+			line   int
+			column int
+		}{
+			{`deploy --env "prod eu`, 1, 14},
+			{"a\n b 'c", 2, 4},
+			{`a\`, 1, 2},
+			{`a ${HOME`, 1, 3},
+			{`a "${}"`, 1, 4},
+		} {
+			_, err := tokenizer.Tokenizer{ExpandEnv: true}.Tokenize(c.text)
+			var syntaxErr *tokenizer.SyntaxError
+			Match(t, true, errors.As(err, &syntaxErr))
+			if syntaxErr != nil {
+				Match(t, c.line, syntaxErr.Line)
+				Match(t, c.column, syntaxErr.Column)
+			}
+		}
+	})
+}
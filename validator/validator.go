@@ -0,0 +1,170 @@
+package validator
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mozzzzy/arguments/v2/argumentOperand"
+	"github.com/mozzzzy/arguments/v2/argumentOption"
+)
+
+/*
+ * Types
+ */
+
+// UseableError is returned by ValidateStringUseable and ValidateIntUseable
+// when the value is not one of the useable values.
+// Value is argumentOption.Redacted if the option is sensitive.
+// Suggestions holds the useable values close to Value, which are set by the caller.
+type UseableError struct {
+	Name        string
+	Value       string
+	Useable     []string
+	Suggestions []string
+}
+
+/*
+ * Constants and Package Scope Variables
+ */
+
+/*
+ * Package Private Functions
+ */
+
+// This function returns the name used in error messages and the value of
+// an argumentOption.Option or an argumentOperand.Operand.
+func getNameAndValue(argIf interface{}) (string, interface{}, error) {
+	switch arg := argIf.(type) {
+	case argumentOption.Option:
+		val, err := arg.GetValue()
+		return fmt.Sprintf("--%v -%v", arg.LongKey, arg.ShortKey), val, err
+	case argumentOperand.Operand:
+		val, err := arg.GetValue()
+		return arg.Key, val, err
+	}
+	return "", nil, errors.New(
+		fmt.Sprintf("Validator can't be used for %T.", argIf))
+}
+
+// This function returns true if neither the value nor the default value of
+// an argumentOption.Option or an argumentOperand.Operand is set.
+func isUnset(argIf interface{}) bool {
+	switch arg := argIf.(type) {
+	case argumentOption.Option:
+		return !arg.Set && arg.DefaultValue == nil
+	case argumentOperand.Operand:
+		return !arg.Set && arg.DefaultValue == nil
+	}
+	return false
+}
+
+// This function returns the value shown in error messages.
+// The value of a sensitive option is redacted.
+func displayValue(argIf interface{}, value interface{}) string {
+	if opt, ok := argIf.(argumentOption.Option); ok && opt.Sensitive {
+		return argumentOption.Redacted
+	}
+	return fmt.Sprint(value)
+}
+
+// This function returns true if validatorFunc is the function target.
+func isValidator(validatorFunc func(interface{}, interface{}) error, target func(interface{}, interface{}) error) bool {
+	if validatorFunc == nil {
+		return false
+	}
+	return reflect.ValueOf(validatorFunc).Pointer() == reflect.ValueOf(target).Pointer()
+}
+
+/*
+ * Public Methods
+ */
+
+func (err *UseableError) Error() string {
+	msg := fmt.Sprintf(
+		"Invalid value of %v \"%v\". Useable values are %v.",
+		err.Name, err.Value, strings.Join(err.Useable, ", "))
+	if len(err.Suggestions) == 0 {
+		return msg
+	}
+	return msg + fmt.Sprintf(" Did you mean \"%v\"?", strings.Join(err.Suggestions, "\" or \""))
+}
+
+/*
+ * Public Functions
+ */
+
+// Useable returns the useable values of ParamString or ParamInt as strings.
+// Hidden and deprecated values are not returned.
+func Useable(paramIf interface{}) []string {
+	useable := []string{}
+	switch param := paramIf.(type) {
+	case ParamString:
+		useable = append(useable, param.Useable...)
+	case ParamInt:
+		for _, integer := range param.Useable {
+			useable = append(useable, fmt.Sprint(integer))
+		}
+	}
+	return useable
+}
+
+// DeprecatedValue returns the replacement of value and true
+// if value is one of the deprecated useable values of ParamString or ParamInt.
+// The replacement is empty if there is no replacement.
+func DeprecatedValue(paramIf interface{}, value interface{}) (string, bool) {
+	switch param := paramIf.(type) {
+	case ParamString:
+		str, ok := value.(string)
+		if !ok {
+			return "", false
+		}
+		replacement, ok := param.DeprecatedUseable[str]
+		return replacement, ok
+	case ParamInt:
+		integer, ok := value.(int)
+		if !ok {
+			return "", false
+		}
+		replacement, ok := param.DeprecatedUseable[integer]
+		return replacement, ok
+	}
+	return "", false
+}
+
+// Choices returns the useable values if validatorFunc is ValidateStringUseable or ValidateIntUseable.
+// Otherwise it returns an empty slice, because the values of paramIf are not checked.
+func Choices(validatorFunc func(interface{}, interface{}) error, paramIf interface{}) []string {
+	if isValidator(validatorFunc, ValidateStringUseable) || isValidator(validatorFunc, ValidateIntUseable) {
+		return Useable(paramIf)
+	}
+	return []string{}
+}
+
+// Constraint describes what validatorFunc checks, like "min: 10, max: 100".
+// It returns an empty string if validatorFunc is not a function of this package.
+func Constraint(validatorFunc func(interface{}, interface{}) error, paramIf interface{}) string {
+	switch {
+	case isValidator(validatorFunc, ValidateIntMin):
+		return fmt.Sprintf("min: %v", paramIf.(ParamInt).Min)
+	case isValidator(validatorFunc, ValidateIntMax):
+		return fmt.Sprintf("max: %v", paramIf.(ParamInt).Max)
+	case isValidator(validatorFunc, ValidateInt):
+		return fmt.Sprintf("min: %v, max: %v", paramIf.(ParamInt).Min, paramIf.(ParamInt).Max)
+	case isValidator(validatorFunc, ValidateStrlenMin):
+		return fmt.Sprintf("length min: %v", paramIf.(ParamString).Min)
+	case isValidator(validatorFunc, ValidateStrlenMax):
+		return fmt.Sprintf("length max: %v", paramIf.(ParamString).Max)
+	case isValidator(validatorFunc, ValidateString):
+		return fmt.Sprintf(
+			"length min: %v, length max: %v", paramIf.(ParamString).Min, paramIf.(ParamString).Max)
+	case isValidator(validatorFunc, ValidateStringUseable), isValidator(validatorFunc, ValidateIntUseable):
+		return "one of: " + strings.Join(Useable(paramIf), ", ")
+	}
+	return ""
+}
@@ -7,8 +7,6 @@ package validator
 import (
 	"errors"
 	"fmt"
-
-	"github.com/mozzzzy/arguments/v2/argumentOption"
 )
 
 /*
@@ -19,6 +17,11 @@ type ParamInt struct {
 	Min     int
 	Max     int
 	Useable []int
+	// Values accepted by ValidateIntUseable but not shown in messages
+	HiddenUseable []int
+	// Values accepted by ValidateIntUseable with a warning naming the replacement.
+	// An empty replacement means that there is no replacement.
+	DeprecatedUseable map[int]string
 }
 
 /*
@@ -30,39 +33,63 @@ type ParamInt struct {
  */
 
 func ValidateIntMin(optIf interface{}, paramIf interface{}) error {
-	opt := optIf.(argumentOption.Option)
 	min := paramIf.(ParamInt).Min
-	val, err := opt.GetValue()
+	name, val, err := getNameAndValue(optIf)
 	if err != nil {
 		return err
 	}
 	if val.(int) < min {
 		return errors.New(
 			fmt.Sprintf(
-				"Invalid value of --%v -%v %v. Value %v is smaller than min %v.",
-				opt.LongKey, opt.ShortKey, val.(int),
+				"Invalid value of %v %v. Value %v is smaller than min %v.",
+				name, val.(int),
 				val.(int), min))
 	}
 	return nil
 }
 
 func ValidateIntMax(optIf interface{}, paramIf interface{}) error {
-	opt := optIf.(argumentOption.Option)
 	max := paramIf.(ParamInt).Max
-	val, err := opt.GetValue()
+	name, val, err := getNameAndValue(optIf)
 	if err != nil {
 		return err
 	}
 	if val.(int) > max {
 		return errors.New(
 			fmt.Sprintf(
-				"Invalid value of --%v -%v %v. Value %v is bigger than max %v.",
-				opt.LongKey, opt.ShortKey, val.(int),
+				"Invalid value of %v %v. Value %v is bigger than max %v.",
+				name, val.(int),
 				val.(int), max))
 	}
 	return nil
 }
 
+func ValidateIntUseable(optIf interface{}, paramIf interface{}) error {
+	// Omitted option or operand without default value has no value to check.
+	if isUnset(optIf) {
+		return nil
+	}
+	useable := paramIf.(ParamInt).Useable
+	name, val, err := getNameAndValue(optIf)
+	if err != nil {
+		return err
+	}
+	for _, integer := range useable {
+		if val.(int) == integer {
+			return nil
+		}
+	}
+	for _, integer := range paramIf.(ParamInt).HiddenUseable {
+		if val.(int) == integer {
+			return nil
+		}
+	}
+	if _, ok := paramIf.(ParamInt).DeprecatedUseable[val.(int)]; ok {
+		return nil
+	}
+	return &UseableError{Name: name, Value: fmt.Sprint(val), Useable: Useable(paramIf)}
+}
+
 func ValidateInt(optIf interface{}, paramIf interface{}) error {
 	if err := ValidateIntMin(optIf, paramIf); err != nil {
 		return err
@@ -7,8 +7,6 @@ package validator
 import (
 	"errors"
 	"fmt"
-
-	"github.com/mozzzzy/arguments/v2/argumentOption"
 )
 
 /*
@@ -19,6 +17,11 @@ type ParamString struct {
 	Min     int
 	Max     int
 	Useable []string
+	// Values accepted by ValidateStringUseable but not shown in messages
+	HiddenUseable []string
+	// Values accepted by ValidateStringUseable with a warning naming the replacement.
+	// An empty replacement means that there is no replacement.
+	DeprecatedUseable map[string]string
 }
 
 /*
@@ -30,39 +33,63 @@ type ParamString struct {
  */
 
 func ValidateStrlenMin(optIf interface{}, paramIf interface{}) error {
-	opt := optIf.(argumentOption.Option)
 	min := paramIf.(ParamString).Min
-	val, err := opt.GetValue()
+	name, val, err := getNameAndValue(optIf)
 	if err != nil {
 		return err
 	}
 	if len(val.(string)) < min {
 		return errors.New(
 			fmt.Sprintf(
-				"Invalid value of --%v -%v \"%v\". String length %v is shorter than min %v.",
-				opt.LongKey, opt.ShortKey, val.(string),
+				"Invalid value of %v \"%v\". String length %v is shorter than min %v.",
+				name, displayValue(optIf, val),
 				len(val.(string)), min))
 	}
 	return nil
 }
 
 func ValidateStrlenMax(optIf interface{}, paramIf interface{}) error {
-	opt := optIf.(argumentOption.Option)
 	max := paramIf.(ParamString).Max
-	val, err := opt.GetValue()
+	name, val, err := getNameAndValue(optIf)
 	if err != nil {
 		return err
 	}
 	if len(val.(string)) > max {
 		return errors.New(
 			fmt.Sprintf(
-				"Invalid value of --%v -%v \"%v\". String length %v is longer than max %v.",
-				opt.LongKey, opt.ShortKey, val.(string),
+				"Invalid value of %v \"%v\". String length %v is longer than max %v.",
+				name, displayValue(optIf, val),
 				len(val.(string)), max))
 	}
 	return nil
 }
 
+func ValidateStringUseable(optIf interface{}, paramIf interface{}) error {
+	// Omitted option or operand without default value has no value to check.
+	if isUnset(optIf) {
+		return nil
+	}
+	useable := paramIf.(ParamString).Useable
+	name, val, err := getNameAndValue(optIf)
+	if err != nil {
+		return err
+	}
+	for _, str := range useable {
+		if val.(string) == str {
+			return nil
+		}
+	}
+	for _, str := range paramIf.(ParamString).HiddenUseable {
+		if val.(string) == str {
+			return nil
+		}
+	}
+	if _, ok := paramIf.(ParamString).DeprecatedUseable[val.(string)]; ok {
+		return nil
+	}
+	return &UseableError{Name: name, Value: displayValue(optIf, val), Useable: Useable(paramIf)}
+}
+
 func ValidateString(optIf interface{}, paramIf interface{}) error {
 	if err := ValidateStrlenMin(optIf, paramIf); err != nil {
 		return err
@@ -0,0 +1,193 @@
+package arguments
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/mozzzzy/arguments/v2/argumentOption"
+	"github.com/mozzzzy/arguments/v2/optionList"
+	"github.com/mozzzzy/arguments/v2/source"
+)
+
+/*
+ * Types
+ */
+
+/*
+ * Constants and Package Scope Variables
+ */
+
+/*
+ * Package Private Functions
+ */
+
+// This function returns true if valueStr given to opt is replaced with the contents of a file or Stdin.
+func isFileValue(opt argumentOption.Option, valueStr string) bool {
+	return opt.ValueFromFile && (valueStr == "-" || isResponseFile(valueStr))
+}
+
+// This function returns the source of the file which path stands for.
+func fileSource(path string) source.Source {
+	if path == "-" {
+		return source.Source{Kind: source.Stdin}
+	}
+	return source.Source{Kind: source.File, Path: path}
+}
+
+// This function returns the source of the value of opt given as valueStr at src.
+func valueSource(opt argumentOption.Option, valueStr string, src source.Source) source.Source {
+	if !isFileValue(opt, valueStr) {
+		return src
+	}
+	return fileSource(strings.TrimPrefix(valueStr, "@"))
+}
+
+// This function removes a newline at the end of the contents of a file, because editors and "echo" add it.
+func trimNewline(str string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(str, "\n"), "\r")
+}
+
+/*
+ * Private Methods
+ */
+
+func (args Args) stdin() io.Reader {
+	if args.Stdin == nil {
+		return os.Stdin
+	}
+	return args.Stdin
+}
+
+func (args Args) maxValueSize() int64 {
+	if args.MaxValueSize == 0 {
+		return DefaultMaxValueSize
+	}
+	return args.MaxValueSize
+}
+
+// This function reads reader up to MaxValueSize.
+func (args Args) readLimited(reader io.Reader) ([]byte, error) {
+	max := args.maxValueSize()
+	if max < 0 {
+		return ioutil.ReadAll(reader)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(reader, max+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > max {
+		return nil, errors.New(fmt.Sprintf("The value is larger than %v bytes.", max))
+	}
+	return data, nil
+}
+
+// This function returns the contents of the file of path, or of Stdin if path is "-".
+// Stdin can be read only once, because the second reader would get nothing.
+// name is the option reading the value, which is used in the error message.
+func (args *Args) readValueFile(name string, path string) (string, error) {
+	if path == "-" {
+		if args.stdinReadBy != "" {
+			return "", errors.New(
+				fmt.Sprintf("Standard input is already read by %v.", args.stdinReadBy))
+		}
+		args.stdinReadBy = name
+		data, err := args.readLimited(args.stdin())
+		return string(data), err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	data, err := args.readLimited(file)
+	return string(data), err
+}
+
+// This function converts the value given in command line to the value of opt.
+// If opt.ValueFromFile is true, "@path" and "-" are replaced with the contents of the file and Stdin
+// without a newline at the end.
+func (args *Args) optionValue(opt argumentOption.Option, valueStr string) (interface{}, error) {
+	name := strings.Fields(opt.Name())[0]
+	if isFileValue(opt, valueStr) {
+		path := strings.TrimPrefix(valueStr, "@")
+		str, err := args.readValueFile(name, path)
+		if err != nil {
+			return nil, errors.New(
+				fmt.Sprintf("Failed to read the value of %v from \"%v\". %v", name, path, err.Error()))
+		}
+		value, err := convertValue(opt.ValueType, trimNewline(str))
+		if err != nil {
+			// The contents of the file is not written in the message.
+			return nil, errors.New(
+				fmt.Sprintf("Failed to parse the value of %v in \"%v\". The value is not %v.",
+					name, path, opt.ValueType))
+		}
+		return value, nil
+	}
+	value, err := convertValue(opt.ValueType, valueStr)
+	if err != nil && opt.Sensitive {
+		return nil, errors.New(
+			fmt.Sprintf("Failed to parse the value of %v. The value is not %v.", name, opt.ValueType))
+	}
+	return value, err
+}
+
+// This function returns true if arg is the key of an option with ValueFromFile, which requires a value.
+// The next argument "@path" is the value of the option, not a response file.
+func (args Args) isFileValueKey(arg string) bool {
+	if !optionList.IsOptKey(arg) {
+		return false
+	}
+	key, err := args.resolveAbbrev(arg)
+	if err != nil {
+		return false
+	}
+	opt, err := args.optionList.GetOpt(key)
+	return err == nil && opt.ValueFromFile && opt.ValueRequired()
+}
+
+// This function sets the values of options with FileKey from the files given to the options of FileKey.
+func (args *Args) readValueFiles() error {
+	for _, opt := range args.optionList.GetOptions() {
+		if opt.FileKey == "" || !args.optionList.IsSet(opt.FileKey) {
+			continue
+		}
+		name := strings.Fields(opt.Name())[0]
+		if opt.Set {
+			return errors.New(
+				fmt.Sprintf("%v and --%v can't be specified together.", name, opt.FileKey))
+		}
+		path, err := args.optionList.GetString(opt.FileKey)
+		if err != nil {
+			return err
+		}
+		str, err := args.readValueFile("--"+opt.FileKey, path)
+		if err != nil {
+			return errors.New(
+				fmt.Sprintf("Failed to read the value of %v from \"%v\". %v", name, path, err.Error()))
+		}
+		// A newline at the end is removed, because editors and "echo" add it.
+		str = strings.TrimSuffix(strings.TrimSuffix(str, "\n"), "\r")
+		value, err := convertValue(opt.ValueType, str)
+		if err != nil {
+			return errors.New(
+				fmt.Sprintf("Failed to parse the value of %v in \"%v\". The value is not %v.",
+					name, path, opt.ValueType))
+		}
+		if err := args.optionList.Set(name, value); err != nil {
+			return err
+		}
+		if err := args.optionList.SetSource(name, fileSource(path)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package arguments
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/mozzzzy/arguments/v2/argumentOption"
+)
+
+/*
+ * Types
+ */
+
+// VersionInfo is the version written by --version.
+// The go command doesn't embed the build time, so CommitTime (vcs.time) is the closest one.
+// Revision, Modified and CommitTime are set only if the program is built with Go 1.18 or later.
+type VersionInfo struct {
+	Program    string `json:"program"`
+	Version    string `json:"version"`
+	Revision   string `json:"revision,omitempty"`
+	Modified   bool   `json:"modified"`
+	CommitTime string `json:"commitTime,omitempty"`
+	GoVersion  string `json:"goVersion,omitempty"`
+}
+
+/*
+ * Constants and Package Scope Variables
+ */
+
+// ErrVersion is returned by Parse when --version is specified and AutoVersion is true.
+// The version has already been written, so the program should exit with status 0.
+var ErrVersion = errors.New("Version is requested.")
+
+var versionFormats = []string{"text", "json"}
+
+/*
+ * Private Methods
+ */
+
+// This function registers --version and also -V unless -V is already used.
+func (args *Args) addVersionOpt() error {
+	if _, err := args.optionList.GetOpt("--version"); err == nil {
+		return nil
+	}
+	opt := argumentOption.Option{
+		LongKey:       "version",
+		ShortKey:      "V",
+		Description:   "show version and exit. FORMAT is text or json.",
+		ValueType:     "string",
+		ValueName:     "FORMAT",
+		ValueOptional: true,
+		ImplicitValue: "text",
+	}
+	if _, err := args.optionList.GetOpt("-V"); err == nil {
+		opt.ShortKey = ""
+	}
+	return args.optionList.AddOption(opt)
+}
+
+func (args Args) printVersion(format string) error {
+	info := args.GetVersionInfo()
+	switch format {
+	case "", "text":
+		fmt.Fprintln(args.output(), info)
+	case "json":
+		data, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(args.output(), string(data))
+	default:
+		return &UnknownArgumentError{
+			Arg:         format,
+			Name:        "--version",
+			Suggestions: args.suggest(format, versionFormats),
+		}
+	}
+	return nil
+}
+
+/*
+ * Public Methods
+ */
+
+// GetVersionInfo returns Version and the build information embedded by the go command.
+// If Version is empty, the version of the main module is used.
+func (args Args) GetVersionInfo() VersionInfo {
+	info := VersionInfo{
+		Program: args.programName(),
+		Version: args.Version,
+	}
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	if info.Version == "" {
+		info.Version = buildInfo.Main.Version
+	}
+	setBuildSettings(&info, buildInfo)
+	return info
+}
+
+func (info VersionInfo) String() string {
+	str := info.Program + " " + info.Version
+	if info.Revision == "" {
+		return str
+	}
+	str += " (revision " + info.Revision
+	if info.Modified {
+		str += ", modified"
+	}
+	if info.CommitTime != "" {
+		str += ", " + info.CommitTime
+	}
+	str += ")"
+	return str
+}
@@ -0,0 +1,31 @@
+//go:build go1.18
+// +build go1.18
+
+package arguments
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"runtime/debug"
+)
+
+/*
+ * Package Private Functions
+ */
+
+// This function sets the Go version and the VCS information embedded since Go 1.18.
+func setBuildSettings(info *VersionInfo, buildInfo *debug.BuildInfo) {
+	info.GoVersion = buildInfo.GoVersion
+	for _, setting := range buildInfo.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		case "vcs.time":
+			info.CommitTime = setting.Value
+		}
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !go1.18
+// +build !go1.18
+
+package arguments
+
+/*
+ * Module Dependencies
+ */
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+/*
+ * Package Private Functions
+ */
+
+// The go command doesn't embed the Go version and the VCS information before Go 1.18,
+// so only the Go version running the program is set.
+func setBuildSettings(info *VersionInfo, buildInfo *debug.BuildInfo) {
+	info.GoVersion = runtime.Version()
+}